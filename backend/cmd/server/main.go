@@ -8,6 +8,7 @@ import (
 	"pea-blog-backend/internal/repository"
 	"pea-blog-backend/internal/scheduler"
 	"pea-blog-backend/internal/service"
+	"pea-blog-backend/internal/storage"
 	"pea-blog-backend/pkg/database"
 	"pea-blog-backend/pkg/logger"
 	"strings"
@@ -19,11 +20,14 @@ func main() {
 	cfg := config.Load()
 	
 	log := logger.New(cfg.Environment)
+	if err := cfg.Media.Validate(); err != nil {
+		log.Fatal("Invalid media configuration", err)
+	}
 
 	// 前端自动构建
 	buildService := frontend.NewBuildService(&cfg.Frontend, log)
 	if err := buildService.CheckAndBuild(); err != nil {
-		log.Error("Frontend build failed", err)
+		log.Error("Frontend build failed", "error", err)
 		// 不退出程序，继续启动服务
 	}
 	
@@ -37,17 +41,25 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to run migrations", err)
 	}
+	if err := repository.Backfill(db); err != nil {
+		log.Fatal("Failed to backfill article data", err)
+	}
+
+	store, err := storage.New(&cfg.Storage, &cfg.Upload)
+	if err != nil {
+		log.Fatal("Failed to initialize storage", err)
+	}
 
 	repos := repository.New(db)
-	services := service.New(repos, log)
+	services := service.New(repos, store, log)
 	handlers := handler.New(services, log)
-	handlers.Image = handler.NewImageHandler(log)
+	handlers.Image = handler.NewImageHandler(&cfg.Upload, store, services.Media, log)
 	
 	// 设置系统处理器
 	handlers.System = handler.NewSystemHandler(buildService)
 
 	// Start the scheduler
-	sched := scheduler.New(services.Article, log)
+	sched := scheduler.New(services.Article, services.Media, &cfg.Media, &cfg.Trash, log)
 	go sched.Start()
 
 	r := gin.New()
@@ -60,6 +72,11 @@ func main() {
 	r.Static("/assets", cfg.Frontend.DistPath+"/assets")
 	r.StaticFile("/", cfg.Frontend.DistPath+"/index.html")
 	r.StaticFile("/favicon.ico", cfg.Frontend.DistPath+"/favicon.ico")
+
+	// 上传文件（仅本地存储需要由本服务提供）
+	if _, ok := store.(*storage.Local); ok {
+		r.Static(cfg.Upload.URLPrefix, cfg.Upload.Dir)
+	}
 	
 	// 对于前端路由，返回index.html让前端路由处理
 	r.NoRoute(func(c *gin.Context) {
@@ -72,44 +89,115 @@ func main() {
 		c.File(cfg.Frontend.DistPath + "/index.html")
 	})
 
+	authRequired := middleware.Auth(services.Auth)
+	authOptional := middleware.OptionalAuth(services.Auth)
+
 	api := r.Group("/api")
 	
 	auth := api.Group("/auth")
 	{
 		auth.POST("/login", handlers.Auth.Login)
-		auth.POST("/logout", middleware.Auth(), handlers.Auth.Logout)
-		auth.GET("/me", middleware.Auth(), handlers.Auth.GetCurrentUser)
+		auth.POST("/logout", authRequired, handlers.Auth.Logout)
+		auth.GET("/me", authRequired, handlers.Auth.GetCurrentUser)
 		auth.POST("/refresh", handlers.Auth.RefreshToken)
+		auth.GET("/sessions", authRequired, handlers.Auth.GetSessions)
+		auth.DELETE("/sessions/:id", authRequired, handlers.Auth.RevokeSession)
+	}
+
+	users := api.Group("/users")
+	{
+		users.DELETE("/:id/sessions", authRequired, middleware.AdminOnly(), handlers.Auth.RevokeUserSessions)
 	}
 
 	articles := api.Group("/articles")
 	{
-		articles.GET("", middleware.Auth(), handlers.Article.GetArticles)
+		articles.GET("", authRequired, handlers.Article.GetArticles)
 		articles.GET("/published", handlers.Article.GetPublishedArticles)
-		articles.GET("/:id", handlers.Article.GetArticleByID)
-		articles.GET("/title/:title", handlers.Article.GetArticleByTitle)
-		articles.GET("/search", handlers.Article.SearchArticles)
-		articles.POST("", middleware.Auth(), middleware.AdminOnly(), handlers.Article.CreateArticle)
-		articles.PUT("/:id", middleware.Auth(), middleware.AdminOnly(), handlers.Article.UpdateArticle)
-		articles.DELETE("/:id", middleware.Auth(), middleware.AdminOnly(), handlers.Article.DeleteArticle)
+		articles.GET("/:id", authOptional, handlers.Article.GetArticleByID)
+		articles.GET("/title/:title", authOptional, handlers.Article.GetArticleByTitle)
+		articles.GET("/slug/:slug", authOptional, handlers.Article.GetArticleBySlug)
+		articles.GET("/preview/:token", handlers.Article.GetArticlePreview)
+		articles.GET("/search", authOptional, handlers.Article.SearchArticles)
+		articles.POST("", authRequired, middleware.AdminOnly(), handlers.Article.CreateArticle)
+		articles.PUT("/:id", authRequired, middleware.AdminOnly(), handlers.Article.UpdateArticle)
+		articles.DELETE("/:id", authRequired, middleware.AdminOnly(), handlers.Article.DeleteArticle)
+		articles.GET("/trash", authRequired, middleware.AdminOnly(), handlers.Article.GetTrash)
+		articles.POST("/trash/:id/restore", authRequired, middleware.AdminOnly(), handlers.Article.RestoreArticle)
+		articles.DELETE("/trash/:id", authRequired, middleware.AdminOnly(), handlers.Article.PurgeArticle)
+		articles.POST("/:id/unlock", handlers.Article.UnlockArticle)
 		articles.POST("/:id/like", handlers.Article.LikeArticle)
 		articles.DELETE("/:id/like", handlers.Article.UnlikeArticle)
-		articles.POST("/:id/unpublish", middleware.Auth(), middleware.AdminOnly(), handlers.Article.UnpublishArticle)
-		// articles.GET("/export", middleware.Auth(), middleware.AdminOnly(), handlers.Article.ExportArticles)
-		// articles.POST("/import", middleware.Auth(), middleware.AdminOnly(), handlers.Article.ImportArticles)
-		articles.GET("/:id/comments", handlers.Comment.GetCommentsByArticleID)
+		articles.POST("/:id/unpublish", authRequired, middleware.AdminOnly(), handlers.Article.UnpublishArticle)
+		articles.PUT("/:id/pin", authRequired, middleware.AdminOnly(), handlers.Article.PinArticle)
+		articles.PUT("/:id/featured", authRequired, middleware.AdminOnly(), handlers.Article.FeatureArticle)
+		articles.POST("/:id/preview", authRequired, middleware.AdminOnly(), handlers.Article.CreatePreview)
+		articles.GET("/export", authRequired, middleware.AdminOnly(), handlers.Article.ExportArticles)
+		articles.POST("/import", authRequired, middleware.AdminOnly(), handlers.Article.ImportArticles)
+		articles.GET("/:id/comments", authOptional, handlers.Comment.GetCommentsByArticleID)
+		articles.GET("/:id/revisions", authRequired, middleware.AdminOnly(), handlers.Article.GetRevisions)
+		articles.GET("/:id/revisions/diff", authRequired, middleware.AdminOnly(), handlers.Article.DiffRevisions)
+		articles.GET("/:id/revisions/:revision", authRequired, middleware.AdminOnly(), handlers.Article.GetRevision)
+		articles.POST("/:id/revisions/:revision/restore", authRequired, middleware.AdminOnly(), handlers.Article.RestoreRevision)
+		articles.GET("/autosave", authRequired, middleware.AdminOnly(), handlers.Article.GetAutosave)
+		articles.PUT("/autosave", authRequired, middleware.AdminOnly(), handlers.Article.SaveAutosave)
+		articles.DELETE("/autosave", authRequired, middleware.AdminOnly(), handlers.Article.DeleteAutosave)
+		articles.POST("/autosave/promote", authRequired, middleware.AdminOnly(), handlers.Article.PromoteAutosave)
+		articles.GET("/:id/autosave", authRequired, middleware.AdminOnly(), handlers.Article.GetAutosave)
+		articles.PUT("/:id/autosave", authRequired, middleware.AdminOnly(), handlers.Article.SaveAutosave)
+		articles.DELETE("/:id/autosave", authRequired, middleware.AdminOnly(), handlers.Article.DeleteAutosave)
+		articles.POST("/:id/autosave/promote", authRequired, middleware.AdminOnly(), handlers.Article.PromoteAutosave)
+	}
+
+	tags := api.Group("/tags")
+	{
+		tags.GET("", handlers.Tag.GetTags)
+		tags.GET("/all", authRequired, middleware.AdminOnly(), handlers.Tag.GetAllTags)
+		tags.POST("/merge", authRequired, middleware.AdminOnly(), handlers.Tag.MergeTags)
+		tags.PUT("/:id", authRequired, middleware.AdminOnly(), handlers.Tag.RenameTag)
+		tags.DELETE("/:id", authRequired, middleware.AdminOnly(), handlers.Tag.DeleteTag)
+	}
+
+	series := api.Group("/series")
+	{
+		series.GET("", authOptional, handlers.Series.GetAllSeries)
+		series.GET("/:id", authOptional, handlers.Series.GetSeries)
+		series.POST("", authRequired, middleware.AdminOnly(), handlers.Series.CreateSeries)
+		series.PUT("/:id", authRequired, middleware.AdminOnly(), handlers.Series.UpdateSeries)
+		series.DELETE("/:id", authRequired, middleware.AdminOnly(), handlers.Series.DeleteSeries)
+	}
+
+	categories := api.Group("/categories")
+	{
+		categories.GET("", authOptional, handlers.Category.GetCategoryTree)
+		categories.POST("", authRequired, middleware.AdminOnly(), handlers.Category.CreateCategory)
+		categories.PUT("/:id", authRequired, middleware.AdminOnly(), handlers.Category.UpdateCategory)
+		categories.DELETE("/:id", authRequired, middleware.AdminOnly(), handlers.Category.DeleteCategory)
+	}
+
+	images := api.Group("/images")
+	{
+		images.POST("/upload", authRequired, middleware.AdminOnly(), handlers.Image.UploadImage)
+	}
+
+	media := api.Group("/media")
+	{
+		media.GET("", authRequired, middleware.AdminOnly(), handlers.Media.GetMedia)
+		media.GET("/orphans", authRequired, middleware.AdminOnly(), handlers.Media.GetOrphans)
+		media.GET("/:id", authRequired, middleware.AdminOnly(), handlers.Media.GetMediaByID)
+		media.PUT("/:id", authRequired, middleware.AdminOnly(), handlers.Media.UpdateMedia)
+		media.DELETE("/:id", authRequired, middleware.AdminOnly(), handlers.Media.DeleteMedia)
 	}
 
 	comments := api.Group("/comments")
 	{
 		comments.POST("", handlers.Comment.CreateComment)
-		comments.DELETE("/:id", handlers.Comment.DeleteComment)
-		comments.GET("/:id/replies", handlers.Comment.GetRepliesByCommentID)
+		comments.DELETE("/:id", authOptional, handlers.Comment.DeleteComment)
+		comments.GET("/:id/replies", authOptional, handlers.Comment.GetRepliesByCommentID)
 	}
 
 	system := api.Group("/system")
 	{
-		system.POST("/rebuild-frontend", middleware.Auth(), middleware.AdminOnly(), handlers.System.RebuildFrontend)
+		system.POST("/rebuild-frontend", authRequired, middleware.AdminOnly(), handlers.System.RebuildFrontend)
 		system.GET("/build-status", handlers.System.GetBuildStatus)
 	}
 
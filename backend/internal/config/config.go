@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +16,10 @@ type Config struct {
 	Database    DatabaseConfig
 	JWT         JWTConfig
 	Frontend    FrontendConfig
+	Upload      UploadConfig
+	Storage     StorageConfig
+	Media       MediaConfig
+	Trash       TrashConfig
 }
 
 type ServerConfig struct {
@@ -35,6 +42,65 @@ type FrontendConfig struct {
 	SourcePath   string
 }
 
+type UploadConfig struct {
+	Dir          string
+	URLPrefix    string
+	MaxSize      int64
+	AllowedTypes []string
+	// ImageWidths 为上传图片生成的缩略图宽度
+	ImageWidths []int
+	JPEGQuality int
+	WebPQuality int
+}
+
+type StorageConfig struct {
+	// Driver 为 local 或 s3
+	Driver string
+	// CDNBaseURL 不为空时，返回的文件 URL 以它为前缀
+	CDNBaseURL string
+	S3         S3Config
+}
+
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+	PathStyle       bool
+	Prefix          string
+}
+
+type MediaConfig struct {
+	// OrphanAction 为定时任务对孤立媒体的处理方式：off、report（只记录日志）或 remove
+	OrphanAction string
+	// OrphanGracePeriod 内上传的文件不视为孤立，避免误删文章尚未保存的图片
+	OrphanGracePeriod time.Duration
+	CleanupInterval   time.Duration
+}
+
+// Validate 检查孤立媒体的处理配置。自动删除时宽限期必须大于 0，
+// 否则刚上传、文章还没保存的图片会在下一次清理时被删除
+func (c *MediaConfig) Validate() error {
+	switch c.OrphanAction {
+	case "off", "report":
+	case "remove":
+		if c.OrphanGracePeriod <= 0 {
+			return fmt.Errorf("MEDIA_ORPHAN_GRACE_HOURS must be greater than 0 when MEDIA_ORPHAN_ACTION is remove")
+		}
+	default:
+		return fmt.Errorf("unsupported MEDIA_ORPHAN_ACTION %q", c.OrphanAction)
+	}
+	return nil
+}
+
+type TrashConfig struct {
+	// Retention 为文章在回收站中保留的时间，超过后由定时任务永久删除；0 表示不自动删除
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
 func Load() *Config {
 	// Load .env file
 	godotenv.Load()
@@ -51,6 +117,37 @@ func Load() *Config {
 	distPath := getEnv("FRONTEND_DIST_PATH", "./frontend/dist")
 	sourcePath := getEnv("FRONTEND_SOURCE_PATH", "./frontend")
 
+	// Upload configuration
+	uploadDir := getEnv("UPLOAD_DIR", "./uploads")
+	uploadURLPrefix := strings.TrimRight(getEnv("UPLOAD_URL_PREFIX", "/uploads"), "/")
+	uploadMaxSizeMB, err := strconv.Atoi(getEnv("UPLOAD_MAX_SIZE_MB", "10"))
+	if err != nil || uploadMaxSizeMB <= 0 {
+		uploadMaxSizeMB = 10
+	}
+	uploadAllowedTypes := splitList(getEnv("UPLOAD_ALLOWED_TYPES", "image/jpeg,image/png,image/gif,image/webp"))
+	var imageWidths []int
+	for _, item := range splitList(getEnv("UPLOAD_IMAGE_WIDTHS", "320,768,1280")) {
+		if width, err := strconv.Atoi(item); err == nil && width > 0 {
+			imageWidths = append(imageWidths, width)
+		}
+	}
+	jpegQuality := getEnvInt("UPLOAD_JPEG_QUALITY", 85, 1, 100)
+	webpQuality := getEnvInt("UPLOAD_WEBP_QUALITY", 80, 1, 100)
+
+	// Storage configuration
+	storageDriver := strings.ToLower(getEnv("STORAGE_DRIVER", "local"))
+	cdnBaseURL := strings.TrimRight(getEnv("STORAGE_CDN_BASE_URL", ""), "/")
+	s3UseSSL, _ := strconv.ParseBool(getEnv("S3_USE_SSL", "true"))
+	s3PathStyle, _ := strconv.ParseBool(getEnv("S3_PATH_STYLE", "false"))
+
+	// Media cleanup configuration
+	orphanAction := strings.ToLower(getEnv("MEDIA_ORPHAN_ACTION", "report"))
+	orphanGraceHours := getEnvInt("MEDIA_ORPHAN_GRACE_HOURS", 24, 0, 24*365)
+	cleanupIntervalHours := getEnvInt("MEDIA_CLEANUP_INTERVAL_HOURS", 24, 1, 24*30)
+
+	// Trash configuration
+	trashRetentionDays := getEnvInt("TRASH_RETENTION_DAYS", 30, 0, 3650)
+
 	return &Config{
 		Environment: environment,
 		Server: ServerConfig{
@@ -69,6 +166,38 @@ func Load() *Config {
 			DistPath:     distPath,
 			SourcePath:   sourcePath,
 		},
+		Upload: UploadConfig{
+			Dir:          uploadDir,
+			URLPrefix:    uploadURLPrefix,
+			MaxSize:      int64(uploadMaxSizeMB) << 20,
+			AllowedTypes: uploadAllowedTypes,
+			ImageWidths:  imageWidths,
+			JPEGQuality:  jpegQuality,
+			WebPQuality:  webpQuality,
+		},
+		Storage: StorageConfig{
+			Driver:     storageDriver,
+			CDNBaseURL: cdnBaseURL,
+			S3: S3Config{
+				Endpoint:        getEnv("S3_ENDPOINT", ""),
+				Region:          getEnv("S3_REGION", "us-east-1"),
+				Bucket:          getEnv("S3_BUCKET", ""),
+				AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+				UseSSL:          s3UseSSL,
+				PathStyle:       s3PathStyle,
+				Prefix:          strings.Trim(getEnv("S3_PREFIX", ""), "/"),
+			},
+		},
+		Media: MediaConfig{
+			OrphanAction:      orphanAction,
+			OrphanGracePeriod: time.Duration(orphanGraceHours) * time.Hour,
+			CleanupInterval:   time.Duration(cleanupIntervalHours) * time.Hour,
+		},
+		Trash: TrashConfig{
+			Retention:     time.Duration(trashRetentionDays) * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
 	}
 }
 
@@ -78,3 +207,22 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt 读取整数配置，无效或超出范围时使用默认值
+func getEnvInt(key string, defaultValue, min, max int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || value < min || value > max {
+		return defaultValue
+	}
+	return value
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
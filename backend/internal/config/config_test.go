@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMediaConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  MediaConfig
+		wantErr bool
+	}{
+		{name: "report without grace period", config: MediaConfig{OrphanAction: "report"}},
+		{name: "remove with grace period", config: MediaConfig{OrphanAction: "remove", OrphanGracePeriod: time.Hour}},
+		{name: "remove without grace period", config: MediaConfig{OrphanAction: "remove"}, wantErr: true},
+		{name: "unknown action", config: MediaConfig{OrphanAction: "delete", OrphanGracePeriod: time.Hour}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -124,7 +124,7 @@ func (b *BuildService) buildFrontend() error {
 	duration := time.Since(start)
 
 	if err != nil {
-		b.logger.Error("Frontend build failed", err)
+		b.logger.Error("Frontend build failed", "error", err)
 		return fmt.Errorf("frontend build failed: %w", err)
 	}
 
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/service"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// autosaveArticleID 返回路由中的文章 ID，新文章的自动保存路由没有 :id，返回 0
+func autosaveArticleID(c *gin.Context) (int, bool) {
+	if c.Param("id") == "" {
+		return 0, true
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid article ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func respondAutosaveError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "article not found", "autosave not found":
+		response.NotFound(c, err.Error())
+	default:
+		response.InternalServerError(c, err.Error())
+	}
+}
+
+func (h *ArticleHandler) SaveAutosave(c *gin.Context) {
+	articleID, ok := autosaveArticleID(c)
+	if !ok {
+		return
+	}
+
+	var req model.AutosaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	autosave, err := h.articleService.SaveAutosave(userID.(int), articleID, req)
+	if err != nil {
+		respondAutosaveError(c, err)
+		return
+	}
+
+	response.Success(c, autosave)
+}
+
+func (h *ArticleHandler) GetAutosave(c *gin.Context) {
+	articleID, ok := autosaveArticleID(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	autosave, err := h.articleService.GetAutosave(userID.(int), articleID)
+	if err != nil {
+		respondAutosaveError(c, err)
+		return
+	}
+
+	response.Success(c, autosave)
+}
+
+func (h *ArticleHandler) DeleteAutosave(c *gin.Context) {
+	articleID, ok := autosaveArticleID(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.articleService.DeleteAutosave(userID.(int), articleID); err != nil {
+		respondAutosaveError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Autosave discarded", nil)
+}
+
+// PromoteAutosave 提交自动保存，请求体可以省略；版本也可以用 If-Match 请求头提交
+func (h *ArticleHandler) PromoteAutosave(c *gin.Context) {
+	articleID, ok := autosaveArticleID(c)
+	if !ok {
+		return
+	}
+
+	var req model.PromoteAutosaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, "Invalid request format")
+		return
+	}
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && articleID != 0 {
+		version, ok := parseArticleETag(ifMatch)
+		if !ok {
+			response.BadRequest(c, "Invalid If-Match header")
+			return
+		}
+		req.Version = &version
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	article, err := h.articleService.PromoteAutosave(userID.(int), articleID, req)
+	if err != nil {
+		var conflict *service.VersionConflictError
+		if errors.As(err, &conflict) {
+			setArticleETag(c, conflict.Current)
+			response.ErrorWithData(c, http.StatusConflict, "Article has been modified since the autosave was started", conflict.Current)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "autosave is incomplete") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		respondAutosaveError(c, err)
+		return
+	}
+
+	setArticleETag(c, article)
+	response.Success(c, article)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"strconv"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/service"
+	"pea-blog-backend/pkg/logger"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService *service.CategoryService
+	logger          *logger.Logger
+}
+
+func NewCategoryHandler(categoryService *service.CategoryService, logger *logger.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		logger:          logger,
+	}
+}
+
+// GetCategoryTree 返回分类树及文章数；管理员的文章数包括未发布的文章
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryService.GetCategoryTree(isAdmin(c))
+	if err != nil {
+		response.InternalServerError(c, "Failed to get categories")
+		return
+	}
+
+	response.Success(c, tree)
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req model.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(&req)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to create category")
+		return
+	}
+
+	response.Success(c, category)
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	var req model.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(id, &req)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to update category")
+		return
+	}
+
+	response.Success(c, category)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(id); err != nil {
+		respondCategoryError(c, err, "Failed to delete category")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Category deleted successfully", nil)
+}
+
+func respondCategoryError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "category not found":
+		response.NotFound(c, "Category not found")
+	case "parent category not found", "invalid parent category", "category name is required":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
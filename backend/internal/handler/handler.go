@@ -1,15 +1,19 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"pea-blog-backend/internal/model"
 	"pea-blog-backend/internal/service"
+	"pea-blog-backend/internal/transfer"
 	"pea-blog-backend/pkg/logger"
 	"pea-blog-backend/pkg/response"
 	"strconv"
 	"strings"
-
-	"pea-blog-backend/internal/util"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,7 +37,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	loginResponse, err := h.authService.Login(req)
+	loginResponse, err := h.authService.Login(req, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		response.Unauthorized(c, err.Error())
 		return
@@ -43,6 +47,17 @@ func (h *AuthHandler) Login(c *gin.Context) {
 }
 
 func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.authService.Logout(userID.(int), c.GetString("sessionID")); err != nil {
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
 	response.SuccessWithMessage(c, "Logged out successfully", nil)
 }
 
@@ -63,7 +78,76 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 }
 
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	response.InternalServerError(c, "Not implemented")
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	loginResponse, err := h.authService.RefreshToken(req, c.ClientIP())
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	response.Success(c, loginResponse)
+}
+
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.authService.GetSessions(userID.(int), c.GetString("sessionID"))
+	if err != nil {
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	err := h.authService.RevokeSession(userID.(int), c.Param("id"))
+	if err != nil {
+		if err.Error() == "session not found" {
+			response.NotFound(c, err.Error())
+		} else {
+			response.InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "Session revoked successfully", nil)
+}
+
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	revoked, err := h.authService.RevokeAllSessions(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, err.Error())
+		} else {
+			response.InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "Sessions revoked successfully", gin.H{"revoked": revoked})
 }
 
 type ArticleHandler struct {
@@ -85,7 +169,8 @@ func (h *ArticleHandler) GetArticles(c *gin.Context) {
 		return
 	}
 
-	params.IncludeDrafts = true
+	// 草稿只对管理员可见
+	params.IncludeDrafts = isAdmin(c)
 	articles, err := h.articleService.GetArticles(params)
 	if err != nil {
 		h.logger.Error("Failed to get articles", "error", err)
@@ -121,12 +206,13 @@ func (h *ArticleHandler) GetArticleByID(c *gin.Context) {
 		return
 	}
 
-	article, err := h.articleService.GetArticleByID(id)
+	article, err := h.articleService.GetArticleByID(id, isAdmin(c), articleAccessToken(c))
 	if err != nil {
 		response.NotFound(c, err.Error())
 		return
 	}
 
+	setArticleETag(c, article)
 	response.Success(c, article)
 }
 
@@ -140,15 +226,67 @@ func (h *ArticleHandler) GetArticleByTitle(c *gin.Context) {
 		return
 	}
 
-	article, err := h.articleService.GetArticleByTitle(decodedTitle)
+	article, err := h.articleService.GetArticleByTitle(decodedTitle, isAdmin(c), articleAccessToken(c))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	setArticleETag(c, article)
+	response.Success(c, article)
+}
+
+// GetArticleBySlug 按 slug 获取文章，旧 slug 以 301 重定向到当前 slug
+func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
+	article, current, err := h.articleService.GetArticleBySlug(c.Param("slug"), isAdmin(c), articleAccessToken(c))
 	if err != nil {
 		response.NotFound(c, err.Error())
 		return
 	}
+	if current != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/articles/slug/"+url.PathEscape(current))
+		return
+	}
 
+	setArticleETag(c, article)
 	response.Success(c, article)
 }
 
+// isArticleInputError 判断创建/更新文章的错误是否由请求内容引起
+func isArticleInputError(err error) bool {
+	switch err.Error() {
+	case "category not found", "password is required for protected articles",
+		"expires_at must be in the future", "expires_at must be after published_at":
+		return true
+	}
+	return false
+}
+
+// isAdmin 判断请求是否来自已登录的管理员，公开接口需配合 OptionalAuth 使用
+func isAdmin(c *gin.Context) bool {
+	role, _ := c.Get("role")
+	return role == "admin"
+}
+
+// setArticleETag 以文章版本号作为 ETag，编辑后提交时放入 If-Match
+func setArticleETag(c *gin.Context, article *model.Article) {
+	c.Header("ETag", fmt.Sprintf("\"%d\"", article.Version))
+}
+
+// parseArticleETag 解析 If-Match 中的版本号，"*" 表示不检查版本（返回 0）
+func parseArticleETag(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return 0, true
+	}
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), "\"")
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
 func (h *ArticleHandler) SearchArticles(c *gin.Context) {
 	var params model.SearchParams
 	if err := c.ShouldBindQuery(&params); err != nil {
@@ -160,8 +298,15 @@ func (h *ArticleHandler) SearchArticles(c *gin.Context) {
 		response.BadRequest(c, "Keyword is required for search")
 		return
 	}
+	if !isAdmin(c) {
+		params.IncludeDrafts = false
+	}
+	// 未指定排序时按相关度排序
+	if c.Query("sort_by") == "" {
+		params.SortBy = "relevance"
+	}
 
-	articles, err := h.articleService.GetArticles(params)
+	articles, err := h.articleService.SearchArticles(params)
 	if err != nil {
 		response.InternalServerError(c, err.Error())
 		return
@@ -185,6 +330,10 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 
 	article, err := h.articleService.CreateArticle(req, userID.(int))
 	if err != nil {
+		if isArticleInputError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, err.Error())
 		return
 	}
@@ -206,12 +355,47 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 		return
 	}
 
-	article, err := h.articleService.UpdateArticle(id, req)
+	// If-Match 优先于请求体中的 version
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, ok := parseArticleETag(ifMatch)
+		if !ok {
+			response.BadRequest(c, "Invalid If-Match header")
+			return
+		}
+		req.Version = &version
+	}
+	if req.Version == nil {
+		response.Error(c, http.StatusPreconditionRequired, "Article version is required, send If-Match or version")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	article, err := h.articleService.UpdateArticle(id, req, userID.(int))
 	if err != nil {
+		var conflict *service.VersionConflictError
+		if errors.As(err, &conflict) {
+			setArticleETag(c, conflict.Current)
+			response.ErrorWithData(c, http.StatusConflict, "Article has been modified by someone else", conflict.Current)
+			return
+		}
+		if err.Error() == "article not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if isArticleInputError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, err.Error())
 		return
 	}
 
+	setArticleETag(c, article)
 	response.Success(c, article)
 }
 
@@ -225,11 +409,15 @@ func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
 
 	err = h.articleService.DeleteArticle(id)
 	if err != nil {
+		if err.Error() == "article not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, err.Error())
 		return
 	}
 
-	response.SuccessWithMessage(c, "Article deleted successfully", nil)
+	response.SuccessWithMessage(c, "Article moved to trash", nil)
 }
 
 func (h *ArticleHandler) LikeArticle(c *gin.Context) {
@@ -243,14 +431,14 @@ func (h *ArticleHandler) LikeArticle(c *gin.Context) {
 	userIDValue, exists := c.Get("userID")
 	if !exists {
 		userID := 0
-		err = h.articleService.LikeArticle(userID, articleID)
+		err = h.articleService.LikeArticle(userID, articleID, articleAccessToken(c))
 	} else {
 		userID := userIDValue.(int)
-		err = h.articleService.LikeArticle(userID, articleID)
+		err = h.articleService.LikeArticle(userID, articleID, articleAccessToken(c))
 	}
 
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		respondArticleAccessError(c, err)
 		return
 	}
 
@@ -268,14 +456,14 @@ func (h *ArticleHandler) UnlikeArticle(c *gin.Context) {
 	userIDValue, exists := c.Get("userID")
 	if !exists {
 		userID := 0
-		err = h.articleService.UnlikeArticle(userID, articleID)
+		err = h.articleService.UnlikeArticle(userID, articleID, articleAccessToken(c))
 	} else {
 		userID := userIDValue.(int)
-		err = h.articleService.UnlikeArticle(userID, articleID)
+		err = h.articleService.UnlikeArticle(userID, articleID, articleAccessToken(c))
 	}
 
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		respondArticleAccessError(c, err)
 		return
 	}
 
@@ -299,6 +487,101 @@ func (h *ArticleHandler) UnpublishArticle(c *gin.Context) {
 	response.SuccessWithMessage(c, "Article unpublished successfully", nil)
 }
 
+// ExportArticles 导出全部文章：默认 ZIP（每篇一个带 front matter 的 Markdown），format=json 时导出单个 JSON
+func (h *ArticleHandler) ExportArticles(c *gin.Context) {
+	format := c.DefaultQuery("format", transfer.FormatZip)
+	if format != transfer.FormatZip && format != transfer.FormatJSON {
+		response.BadRequest(c, "Unsupported export format")
+		return
+	}
+
+	articles, err := h.articleService.GetAllArticlesForExport()
+	if err != nil {
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	filename := "pea-blog-export-" + time.Now().Format("20060102-150405") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if format == transfer.FormatJSON {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		err = transfer.WriteJSON(c.Writer, articles)
+	} else {
+		c.Header("Content-Type", "application/zip")
+		err = transfer.WriteZip(c.Writer, articles)
+	}
+	if err != nil {
+		// 响应头已发送，只能记录日志
+		h.logger.Error("Failed to write export", "format", format, "error", err)
+		return
+	}
+
+	h.logger.Info("Articles exported", "format", format, "count", len(articles))
+}
+
+const maxImportFileSize = 64 << 20
+
+// ImportArticles 导入导出的 ZIP、带 front matter 的 Markdown 或 JSON 文件
+func (h *ArticleHandler) ImportArticles(c *gin.Context) {
+	var opts model.ImportOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "Invalid file")
+		return
+	}
+	if file.Size > maxImportFileSize {
+		response.BadRequest(c, "Import file is too large")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		response.BadRequest(c, "Invalid file")
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		response.BadRequest(c, "Invalid file")
+		return
+	}
+
+	entries, err := transfer.Parse(file.Filename, data)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	report, err := h.articleService.ImportArticles(entries, opts, userID.(int))
+	if err != nil {
+		if errors.Is(err, service.ErrImportFailed) {
+			response.ErrorWithData(c, http.StatusUnprocessableEntity, err.Error(), report)
+		} else {
+			response.InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	message := "Articles imported successfully"
+	if opts.DryRun {
+		message = "Dry run completed, no changes were saved"
+	}
+	response.SuccessWithMessage(c, message, report)
+}
+
 type CommentHandler struct {
 	commentService *service.CommentService
 	logger         *logger.Logger
@@ -322,9 +605,9 @@ func (h *CommentHandler) GetCommentsByArticleID(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "15"))
 
-	comments, err := h.commentService.GetCommentsByArticleID(articleID, page, pageSize)
+	comments, err := h.commentService.GetCommentsByArticleID(articleID, page, pageSize, isAdmin(c), articleAccessToken(c))
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		respondArticleAccessError(c, err)
 		return
 	}
 
@@ -342,9 +625,9 @@ func (h *CommentHandler) GetRepliesByCommentID(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "15"))
 
-	replies, err := h.commentService.GetRepliesByCommentID(commentID, page, pageSize)
+	replies, err := h.commentService.GetRepliesByCommentID(commentID, page, pageSize, isAdmin(c), articleAccessToken(c))
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		respondArticleAccessError(c, err)
 		return
 	}
 
@@ -368,9 +651,9 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		fingerprint = req.Fingerprint
 	}
 
-	comment, err := h.commentService.CreateComment(req, authorID, fingerprint)
+	comment, err := h.commentService.CreateComment(req, authorID, fingerprint, articleAccessToken(c))
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		respondArticleAccessError(c, err)
 		return
 	}
 
@@ -389,16 +672,9 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	var isAdmin bool
 	var fingerprint *string
 
-	authHeader := c.GetHeader("Authorization")
-	if authHeader != "" {
-		bearerToken := strings.Split(authHeader, " ")
-		if len(bearerToken) == 2 && bearerToken[0] == "Bearer" {
-			claims, err := util.ValidateJWT(bearerToken[1])
-			if err == nil {
-				authorID = claims.UserID
-				isAdmin = claims.Role == "admin"
-			}
-		}
+	if userIDValue, exists := c.Get("userID"); exists {
+		authorID = userIDValue.(int)
+		isAdmin = c.GetString("role") == "admin"
 	}
 
 	if authorID == 0 {
@@ -423,19 +699,133 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	response.SuccessWithMessage(c, "Comment deleted successfully", nil)
 }
 
+type TagHandler struct {
+	tagService *service.TagService
+	logger     *logger.Logger
+}
+
+func NewTagHandler(tagService *service.TagService, logger *logger.Logger) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+func (h *TagHandler) GetTags(c *gin.Context) {
+	tags, err := h.tagService.GetTags()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get tags")
+		return
+	}
+
+	response.Success(c, tags)
+}
+
+func (h *TagHandler) GetAllTags(c *gin.Context) {
+	tags, err := h.tagService.GetAllTags()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get tags")
+		return
+	}
+
+	response.Success(c, tags)
+}
+
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	var req model.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tag, err := h.tagService.RenameTag(id, &req)
+	if err != nil {
+		switch err.Error() {
+		case "tag not found":
+			response.NotFound(c, "Tag not found")
+		case "tag already exists":
+			response.Error(c, http.StatusConflict, "Tag already exists, merge the tags instead")
+		case "tag name is required":
+			response.BadRequest(c, err.Error())
+		default:
+			response.InternalServerError(c, "Failed to rename tag")
+		}
+		return
+	}
+
+	response.Success(c, tag)
+}
+
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	var req model.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	affected, err := h.tagService.MergeTags(&req)
+	if err != nil {
+		switch err.Error() {
+		case "tag not found":
+			response.NotFound(c, "Tag not found")
+		case "no source tags to merge":
+			response.BadRequest(c, err.Error())
+		default:
+			response.InternalServerError(c, "Failed to merge tags")
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "Tags merged successfully", gin.H{"affected_articles": affected})
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	affected, err := h.tagService.DeleteTag(id)
+	if err != nil {
+		if err.Error() == "tag not found" {
+			response.NotFound(c, "Tag not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to delete tag")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Tag deleted successfully", gin.H{"affected_articles": affected})
+}
+
 type Handler struct {
-	Auth    *AuthHandler
-	Article *ArticleHandler
-	Comment *CommentHandler
-	System  *SystemHandler
-	Image   *ImageHandler
+	Auth     *AuthHandler
+	Article  *ArticleHandler
+	Comment  *CommentHandler
+	System   *SystemHandler
+	Image    *ImageHandler
+	Media    *MediaHandler
+	Tag      *TagHandler
+	Series   *SeriesHandler
+	Category *CategoryHandler
 }
 
 func New(services *service.Service, logger *logger.Logger) *Handler {
 	return &Handler{
-		Auth:    NewAuthHandler(services.Auth, logger),
-		Article: NewArticleHandler(services.Article, logger),
-		Comment: NewCommentHandler(services.Comment, logger),
-		System:  nil, // 在main.go中单独设置
+		Auth:     NewAuthHandler(services.Auth, logger),
+		Article:  NewArticleHandler(services.Article, logger),
+		Comment:  NewCommentHandler(services.Comment, logger),
+		Media:    NewMediaHandler(services.Media, logger),
+		Tag:      NewTagHandler(services.Tag, logger),
+		Series:   NewSeriesHandler(services.Series, logger),
+		Category: NewCategoryHandler(services.Category, logger),
+		System:   nil, // 在main.go中单独设置
 	}
 }
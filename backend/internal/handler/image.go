@@ -1,10 +1,22 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
+	"image/gif"
+	"io"
+	"net/http"
 	"path/filepath"
 
+	"pea-blog-backend/internal/config"
+	"pea-blog-backend/internal/imaging"
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/service"
+	"pea-blog-backend/internal/storage"
 	"pea-blog-backend/pkg/logger"
 	"pea-blog-backend/pkg/response"
 
@@ -12,45 +24,212 @@ import (
 	"github.com/google/uuid"
 )
 
+// 按嗅探出的 MIME 类型决定扩展名，不信任客户端提供的文件名
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"image/bmp":  ".bmp",
+}
+
 type ImageHandler struct {
-	logger *logger.Logger
+	config       *config.UploadConfig
+	storage      storage.Storage
+	mediaService *service.MediaService
+	logger       *logger.Logger
 }
 
-func NewImageHandler(logger *logger.Logger) *ImageHandler {
-	return &ImageHandler{logger: logger}
+func NewImageHandler(cfg *config.UploadConfig, store storage.Storage, mediaService *service.MediaService, logger *logger.Logger) *ImageHandler {
+	return &ImageHandler{config: cfg, storage: store, mediaService: mediaService, logger: logger}
 }
 
 func (h *ImageHandler) UploadImage(c *gin.Context) {
+	// 预留 1MB 给 multipart 的其他部分
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.config.MaxSize+1<<20)
+
 	file, err := c.FormFile("file")
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(c, http.StatusRequestEntityTooLarge, "File is too large")
+			return
+		}
 		response.BadRequest(c, "Invalid file")
 		return
 	}
+	if file.Size > h.config.MaxSize {
+		response.Error(c, http.StatusRequestEntityTooLarge, "File is too large")
+		return
+	}
 
-	extension := filepath.Ext(file.Filename)
-	newFileName := uuid.New().String() + extension
-	
-	wd, err := os.Getwd()
+	src, err := file.Open()
 	if err != nil {
-		h.logger.Error("Failed to get working directory", "error", err)
-		response.InternalServerError(c, "Failed to upload image")
+		response.BadRequest(c, "Invalid file")
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		response.BadRequest(c, "Invalid file")
 		return
 	}
+	contentType := http.DetectContentType(data)
 
-	uploadPath := filepath.Join(wd, "..", "frontend", "public", "uploads")
-	if err := os.MkdirAll(uploadPath, os.ModePerm); err != nil {
-		h.logger.Error("Failed to create upload directory", "error", err)
+	extension, ok := imageExtensions[contentType]
+	if !ok || !h.isAllowed(contentType) {
+		response.Error(c, http.StatusUnsupportedMediaType, "Unsupported file type: "+contentType)
+		return
+	}
+
+	hash := sha256.Sum256(data)
+	checksum := hex.EncodeToString(hash[:])
+
+	// 相同内容只保存一份
+	existing, err := h.mediaService.FindDuplicate(checksum)
+	if err != nil {
+		h.logger.Error("Failed to look up media", "error", err)
 		response.InternalServerError(c, "Failed to upload image")
 		return
 	}
+	if existing != nil {
+		uploaded := h.mediaService.UploadedImage(existing)
+		uploaded.Duplicate = true
+		response.Success(c, uploaded)
+		return
+	}
+
+	media := &model.Media{
+		OriginalName: truncateFileName(file.Filename),
+		SHA256:       checksum,
+		AltText:      c.PostForm("alt"),
+	}
+	if userID, exists := c.Get("userID"); exists {
+		id := userID.(int)
+		media.UploaderID = &id
+	}
+
+	baseName := uuid.New().String()
+	ctx := c.Request.Context()
 
-	dst := filepath.Join(uploadPath, newFileName)
-	if err := c.SaveUploadedFile(file, dst); err != nil {
+	var saved []string
+	if contentType == "image/gif" {
+		// GIF 可能是动图，原样保存
+		media.FileName = baseName + extension
+		media.MimeType = contentType
+		media.Size = int64(len(data))
+		if config, err := gif.DecodeConfig(bytes.NewReader(data)); err == nil {
+			media.Width, media.Height = config.Width, config.Height
+		}
+		err = h.storage.Put(ctx, media.FileName, bytes.NewReader(data), media.Size, contentType)
+		if err == nil {
+			saved = append(saved, media.FileName)
+		}
+	} else {
+		processed, processErr := imaging.Process(data, contentType, imaging.Options{
+			Widths:      h.config.ImageWidths,
+			JPEGQuality: h.config.JPEGQuality,
+			WebPQuality: float32(h.config.WebPQuality),
+		})
+		if processErr != nil {
+			if errors.Is(processErr, imaging.ErrTooManyPixels) {
+				response.BadRequest(c, "Image dimensions are too large")
+				return
+			}
+			h.logger.Warn("Failed to process uploaded image", "error", processErr)
+			response.BadRequest(c, "Invalid image")
+			return
+		}
+		saved, err = h.store(ctx, baseName, processed, media)
+	}
+	if err == nil {
+		err = h.mediaService.CreateMedia(media)
+	}
+	if err != nil {
 		h.logger.Error("Failed to save uploaded file", "error", err)
+		h.cleanup(saved)
 		response.InternalServerError(c, "Failed to upload image")
 		return
 	}
 
-	imageUrl := fmt.Sprintf("/uploads/%s", newFileName)
-	response.Success(c, gin.H{"url": imageUrl})
-}
\ No newline at end of file
+	h.logger.Info("Image uploaded", "media", media.ID, "file", media.FileName, "contentType", media.MimeType,
+		"size", file.Size, "width", media.Width, "height", media.Height, "variants", len(media.Files))
+	response.Success(c, h.mediaService.UploadedImage(media))
+}
+
+// store 保存原图及全部缩略图并把文件名记录到 media，返回已保存的文件以便失败时清理
+func (h *ImageHandler) store(ctx context.Context, baseName string, processed *imaging.Result, media *model.Media) ([]string, error) {
+	var saved []string
+	put := func(key string, img imaging.Image) error {
+		if err := h.storage.Put(ctx, key, bytes.NewReader(img.Data), int64(len(img.Data)), img.ContentType); err != nil {
+			return err
+		}
+		saved = append(saved, key)
+		return nil
+	}
+
+	media.FileName = baseName + processed.Original.Extension
+	media.MimeType = processed.Original.ContentType
+	media.Size = int64(len(processed.Original.Data))
+	media.Width = processed.Original.Width
+	media.Height = processed.Original.Height
+	if err := put(media.FileName, processed.Original); err != nil {
+		return saved, err
+	}
+	// 未启用 cgo 构建时没有 WebP 版本，见 imaging.WebPSupported
+	if processed.WebP.ContentType == "image/webp" {
+		media.WebPFileName = baseName + processed.WebP.Extension
+		if processed.WebP.Extension != processed.Original.Extension {
+			if err := put(media.WebPFileName, processed.WebP); err != nil {
+				return saved, err
+			}
+		}
+	}
+
+	for _, v := range processed.Variants {
+		name := fmt.Sprintf("%s-%dw", baseName, v.Width)
+		file := model.MediaFile{Width: v.Width, Height: v.Height, Key: name + v.Image.Extension}
+		if err := put(file.Key, v.Image); err != nil {
+			return saved, err
+		}
+		if v.WebP.ContentType == "image/webp" {
+			file.WebPKey = name + v.WebP.Extension
+		}
+		if file.WebPKey != "" && v.WebP.Extension != v.Image.Extension {
+			if err := put(file.WebPKey, v.WebP); err != nil {
+				return saved, err
+			}
+		}
+		media.Files = append(media.Files, file)
+	}
+
+	return saved, nil
+}
+
+func (h *ImageHandler) cleanup(keys []string) {
+	for _, key := range keys {
+		if err := h.storage.Delete(context.Background(), key); err != nil {
+			h.logger.Warn("Failed to remove partially uploaded file", "file", key, "error", err)
+		}
+	}
+}
+
+// truncateFileName 限制原始文件名长度，只用于展示和搜索
+func truncateFileName(name string) string {
+	name = filepath.Base(name)
+	runes := []rune(name)
+	if len(runes) > 200 {
+		return string(runes[:200])
+	}
+	return name
+}
+
+func (h *ImageHandler) isAllowed(contentType string) bool {
+	for _, allowed := range h.config.AllowedTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pea-blog-backend/internal/config"
+	"pea-blog-backend/internal/repository"
+	"pea-blog-backend/internal/service"
+	"pea-blog-backend/internal/storage"
+	"pea-blog-backend/pkg/database"
+	"pea-blog-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestImageHandler(t *testing.T) (*ImageHandler, string) {
+	t.Helper()
+	db, err := database.Connect(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "uploads")
+	cfg := &config.UploadConfig{
+		Dir:          dir,
+		URLPrefix:    "/uploads",
+		MaxSize:      1 << 20,
+		AllowedTypes: []string{"image/jpeg", "image/png"},
+		JPEGQuality:  85,
+		WebPQuality:  80,
+	}
+	store := storage.NewLocal(dir, cfg.URLPrefix, "")
+	log := logger.New("test")
+	services := service.New(repository.New(db), store, log)
+	return NewImageHandler(cfg, store, services.Media, log), dir
+}
+
+func uploadRequest(t *testing.T, h *ImageHandler, fileName string, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/upload/image", &body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	h.UploadImage(c)
+	return w
+}
+
+func TestUploadImage(t *testing.T) {
+	h, dir := newTestImageHandler(t)
+
+	// 扩展名不可信，按内容嗅探
+	w := uploadRequest(t, h, "notes.png", []byte("<?php echo 'hi'; ?>"))
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("text disguised as png = %d, want %d", w.Code, http.StatusUnsupportedMediaType)
+	}
+
+	w = uploadRequest(t, h, "big.png", bytes.Repeat([]byte{0}, 2<<20))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized file = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	w = uploadRequest(t, h, "../../evil.php", buf.Bytes())
+	if w.Code != http.StatusOK {
+		t.Fatalf("png upload = %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !strings.HasPrefix(resp.Data.URL, "/uploads/") || !strings.HasSuffix(resp.Data.URL, ".png") {
+		t.Errorf("url = %q, want a generated .png name under /uploads", resp.Data.URL)
+	}
+	if _, err := os.Stat(filepath.Join(dir, strings.TrimPrefix(resp.Data.URL, "/uploads/"))); err != nil {
+		t.Errorf("uploaded file missing: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/service"
+	"pea-blog-backend/pkg/logger"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler struct {
+	mediaService *service.MediaService
+	logger       *logger.Logger
+}
+
+func NewMediaHandler(mediaService *service.MediaService, logger *logger.Logger) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		logger:       logger,
+	}
+}
+
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	var params model.MediaSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	media, err := h.mediaService.GetMedia(params)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get media")
+		return
+	}
+
+	response.Success(c, media)
+}
+
+func (h *MediaHandler) GetMediaByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid media ID")
+		return
+	}
+
+	media, err := h.mediaService.GetMediaByID(id)
+	if err != nil {
+		if err.Error() == "media not found" {
+			response.NotFound(c, "Media not found")
+			return
+		}
+		h.logger.Error("Failed to get media", "id", id, "error", err)
+		response.InternalServerError(c, "Failed to get media")
+		return
+	}
+
+	response.Success(c, media)
+}
+
+func (h *MediaHandler) UpdateMedia(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid media ID")
+		return
+	}
+
+	var req model.UpdateMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	media, err := h.mediaService.UpdateMedia(id, &req)
+	if err != nil {
+		if err.Error() == "media not found" {
+			response.NotFound(c, "Media not found")
+			return
+		}
+		h.logger.Error("Failed to update media", "id", id, "error", err)
+		response.InternalServerError(c, "Failed to update media")
+		return
+	}
+
+	response.Success(c, media)
+}
+
+// DeleteMedia 删除媒体及其所有缩略图；仍被文章引用时返回 409，可用 force=true 强制删除
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid media ID")
+		return
+	}
+	force, _ := strconv.ParseBool(c.Query("force"))
+
+	if err := h.mediaService.DeleteMedia(id, force); err != nil {
+		switch err.Error() {
+		case "media not found":
+			response.NotFound(c, "Media not found")
+		case "media is in use":
+			response.Error(c, http.StatusConflict, "Media is still referenced by an article")
+		default:
+			h.logger.Error("Failed to delete media", "id", id, "error", err)
+			response.InternalServerError(c, "Failed to delete media")
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "Media deleted successfully", nil)
+}
+
+// GetOrphans 列出未被任何文章引用的媒体，grace_hours 指定忽略最近多少小时内的上传
+func (h *MediaHandler) GetOrphans(c *gin.Context) {
+	graceHours, err := strconv.Atoi(c.DefaultQuery("grace_hours", "24"))
+	if err != nil || graceHours < 0 {
+		response.BadRequest(c, "Invalid grace_hours")
+		return
+	}
+
+	orphans, err := h.mediaService.FindOrphans(time.Duration(graceHours) * time.Hour)
+	if err != nil {
+		h.logger.Error("Failed to find orphaned media", "error", err)
+		response.InternalServerError(c, "Failed to find orphaned media")
+		return
+	}
+
+	response.Success(c, gin.H{"media": orphans, "total": len(orphans)})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"strconv"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PinArticle 置顶或取消置顶文章，置顶的文章在列表中始终排在最前
+func (h *ArticleHandler) PinArticle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+
+	var req model.PinArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	if err := h.articleService.PinArticle(id, &req); err != nil {
+		respondFlagError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Article pin updated successfully", nil)
+}
+
+// FeatureArticle 设置或取消精选，精选文章可通过 featured=true 筛选
+func (h *ArticleHandler) FeatureArticle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+
+	var req model.FeatureArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	if err := h.articleService.FeatureArticle(id, req.Featured); err != nil {
+		respondFlagError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Article featured updated successfully", nil)
+}
+
+func respondFlagError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "article not found":
+		response.NotFound(c, err.Error())
+	case "pinned_until must be in the future":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/service"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePreview 为文章生成预览链接，请求体可以省略
+func (h *ArticleHandler) CreatePreview(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+
+	var req model.CreatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	ttl := service.DefaultPreviewTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	preview, err := h.articleService.CreatePreviewToken(id, ttl)
+	if err != nil {
+		if err.Error() == "article not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, preview)
+}
+
+// GetArticlePreview 凭预览令牌查看文章，无需登录
+func (h *ArticleHandler) GetArticlePreview(c *gin.Context) {
+	article, err := h.articleService.GetArticlePreview(c.Param("token"))
+	if err != nil {
+		if err.Error() == "article not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.Forbidden(c, err.Error())
+		return
+	}
+
+	// 预览内容不应被缓存或被搜索引擎收录
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	response.Success(c, article)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"strconv"
+
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *ArticleHandler) GetRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+
+	revisions, err := h.articleService.GetRevisions(id)
+	if err != nil {
+		if err.Error() == "article not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, revisions)
+}
+
+func (h *ArticleHandler) GetRevision(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		response.BadRequest(c, "Invalid revision")
+		return
+	}
+
+	result, err := h.articleService.GetRevision(id, revision)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// DiffRevisions 比较 from 与 to 两个版本，to 省略或为 current 时与当前内容比较
+func (h *ArticleHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from <= 0 {
+		response.BadRequest(c, "Invalid from revision")
+		return
+	}
+	to := 0
+	if value := c.Query("to"); value != "" && value != "current" {
+		if to, err = strconv.Atoi(value); err != nil || to <= 0 {
+			response.BadRequest(c, "Invalid to revision")
+			return
+		}
+	}
+
+	diff, err := h.articleService.DiffRevisions(id, from, to)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, diff)
+}
+
+func (h *ArticleHandler) RestoreRevision(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		response.BadRequest(c, "Invalid revision")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	article, err := h.articleService.RestoreRevision(id, revision, userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "revision not found", "article not found":
+			response.NotFound(c, err.Error())
+		default:
+			response.InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "Revision restored successfully", article)
+}
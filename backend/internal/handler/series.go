@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/service"
+	"pea-blog-backend/pkg/logger"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesHandler struct {
+	seriesService *service.SeriesService
+	logger        *logger.Logger
+}
+
+func NewSeriesHandler(seriesService *service.SeriesService, logger *logger.Logger) *SeriesHandler {
+	return &SeriesHandler{
+		seriesService: seriesService,
+		logger:        logger,
+	}
+}
+
+// GetAllSeries 列出系列；管理员可以看到未发布的文章和空系列
+func (h *SeriesHandler) GetAllSeries(c *gin.Context) {
+	seriesList, err := h.seriesService.GetAllSeries(isAdmin(c))
+	if err != nil {
+		response.InternalServerError(c, "Failed to get series")
+		return
+	}
+
+	response.Success(c, seriesList)
+}
+
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid series ID")
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(id, isAdmin(c))
+	if err != nil {
+		if err.Error() == "series not found" {
+			response.NotFound(c, "Series not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get series")
+		return
+	}
+
+	response.Success(c, series)
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var req model.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	series, err := h.seriesService.CreateSeries(&req)
+	if err != nil {
+		respondSeriesError(c, err, "Failed to create series")
+		return
+	}
+
+	response.Success(c, series)
+}
+
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid series ID")
+		return
+	}
+
+	var req model.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	series, err := h.seriesService.UpdateSeries(id, &req)
+	if err != nil {
+		respondSeriesError(c, err, "Failed to update series")
+		return
+	}
+
+	response.Success(c, series)
+}
+
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid series ID")
+		return
+	}
+
+	if err := h.seriesService.DeleteSeries(id); err != nil {
+		respondSeriesError(c, err, "Failed to delete series")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Series deleted successfully", nil)
+}
+
+func respondSeriesError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "series not found":
+		response.NotFound(c, "Series not found")
+	case "article not found":
+		response.BadRequest(c, "Article not found")
+	case "series already exists":
+		response.Error(c, http.StatusConflict, "Series already exists")
+	case "series title is required":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
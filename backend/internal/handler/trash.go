@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"strconv"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *ArticleHandler) GetTrash(c *gin.Context) {
+	var params model.SearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	articles, err := h.articleService.GetTrash(params)
+	if err != nil {
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, articles)
+}
+
+func (h *ArticleHandler) RestoreArticle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+
+	if err := h.articleService.RestoreArticle(id); err != nil {
+		if err.Error() == "article not found in trash" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "Article restored successfully", nil)
+}
+
+func (h *ArticleHandler) PurgeArticle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+
+	if err := h.articleService.PurgeArticle(id); err != nil {
+		if err.Error() == "article not found in trash" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "Article permanently deleted", nil)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"strconv"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// articleAccessToken 返回读者解锁受密码保护文章后获得的访问令牌
+func articleAccessToken(c *gin.Context) string {
+	return c.GetHeader("X-Article-Token")
+}
+
+// UnlockArticle 校验受密码保护文章的密码，返回短期有效的访问令牌
+func (h *ArticleHandler) UnlockArticle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid article ID")
+		return
+	}
+
+	var req model.UnlockArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	token, err := h.articleService.UnlockArticle(id, req.Password)
+	if err != nil {
+		switch err.Error() {
+		case "article not found":
+			response.NotFound(c, err.Error())
+		case "article is not password protected":
+			response.BadRequest(c, err.Error())
+		case "incorrect password":
+			response.Forbidden(c, "Incorrect password")
+		default:
+			response.InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, token)
+}
+
+// respondArticleAccessError 响应评论、点赞时文章访问检查失败等错误
+func respondArticleAccessError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "article not found", "comment not found":
+		response.NotFound(c, err.Error())
+	case "article is password protected":
+		response.Forbidden(c, err.Error())
+	default:
+		response.InternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,170 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"sort"
+
+	_ "golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// 解码前检查像素数，防止解压炸弹占满内存
+const maxPixels = 50_000_000
+
+var ErrTooManyPixels = errors.New("image dimensions are too large")
+
+type Options struct {
+	// Widths 为需要生成的宽度，大于等于原图宽度的会被跳过
+	Widths      []int
+	JPEGQuality int
+	WebPQuality float32
+}
+
+// Image 是一个编码后的图片文件
+type Image struct {
+	Width       int
+	Height      int
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+// Variant 是某个宽度下的原格式和 WebP 两个版本
+type Variant struct {
+	Width  int
+	Height int
+	Image  Image
+	WebP   Image
+}
+
+type Result struct {
+	// Original 为转正并去除元数据后重新编码的原尺寸图片
+	Original Image
+	// WebP 为原尺寸的 WebP 版本，不支持 WebP 编码时与 Original 相同
+	WebP     Image
+	Variants []Variant
+}
+
+// Process 转正、去除 EXIF 等元数据，并生成各宽度的缩略图及 WebP 版本。
+// GIF 可能是动图，重新编码会丢帧，由调用方原样保存。
+func Process(data []byte, contentType string, opts Options) (*Result, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	img := toNRGBA(decoded)
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	// 原格式：JPEG、WebP 保持不变，PNG 仍编码为 PNG 以保留透明度；
+	// 不支持 WebP 编码时 WebP 图片改存为 PNG
+	var encode func(image.Image) (Image, error)
+	switch {
+	case contentType == "image/jpeg":
+		encode = func(m image.Image) (Image, error) { return encodeJPEG(m, opts.JPEGQuality) }
+	case contentType == "image/webp" && WebPSupported:
+		encode = func(m image.Image) (Image, error) { return encodeWebP(m, opts.WebPQuality) }
+	default:
+		encode = encodePNG
+	}
+	// 不支持 WebP 编码时返回原格式的图片，调用方按 ContentType 判断是否有 WebP 版本
+	webpCopy := func(m image.Image, encoded Image) (Image, error) {
+		if encoded.ContentType == "image/webp" || !WebPSupported {
+			return encoded, nil
+		}
+		return encodeWebP(m, opts.WebPQuality)
+	}
+
+	result := &Result{}
+	if result.Original, err = encode(img); err != nil {
+		return nil, err
+	}
+	if result.WebP, err = webpCopy(img, result.Original); err != nil {
+		return nil, err
+	}
+
+	widths := append([]int(nil), opts.Widths...)
+	sort.Ints(widths)
+	for _, width := range widths {
+		if width <= 0 || width >= img.Bounds().Dx() {
+			continue
+		}
+
+		resized := resize(img, width)
+		variant := Variant{Width: resized.Bounds().Dx(), Height: resized.Bounds().Dy()}
+		if variant.Image, err = encode(resized); err != nil {
+			return nil, err
+		}
+		if variant.WebP, err = webpCopy(resized, variant.Image); err != nil {
+			return nil, err
+		}
+		result.Variants = append(result.Variants, variant)
+	}
+
+	return result, nil
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok && img.Bounds().Min == (image.Point{}) {
+		return img
+	}
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+func resize(src *image.NRGBA, width int) *image.NRGBA {
+	bounds := src.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+func encodeJPEG(img image.Image, quality int) (Image, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return Image{}, fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return newImage(img, "image/jpeg", ".jpg", buf.Bytes()), nil
+}
+
+func encodePNG(img image.Image) (Image, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return Image{}, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return newImage(img, "image/png", ".png", buf.Bytes()), nil
+}
+
+func newImage(img image.Image, contentType, extension string, data []byte) Image {
+	return Image{
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		ContentType: contentType,
+		Extension:   extension,
+		Data:        data,
+	}
+}
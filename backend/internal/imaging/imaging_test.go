@@ -0,0 +1,39 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestProcessWebPCopies(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		src.Set(x, x%20, color.NRGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatalf("encode source: %v", err)
+	}
+
+	result, err := Process(buf.Bytes(), "image/png", Options{Widths: []int{20}, JPEGQuality: 80, WebPQuality: 80})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if len(result.Variants) != 1 {
+		t.Fatalf("variants = %d, want 1", len(result.Variants))
+	}
+
+	want := "image/png"
+	if WebPSupported {
+		want = "image/webp"
+	}
+	if result.WebP.ContentType != want {
+		t.Errorf("webp copy = %q, want %q", result.WebP.ContentType, want)
+	}
+	if result.Variants[0].WebP.ContentType != want {
+		t.Errorf("variant webp copy = %q, want %q", result.Variants[0].WebP.ContentType, want)
+	}
+}
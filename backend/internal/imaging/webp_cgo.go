@@ -0,0 +1,22 @@
+//go:build cgo
+
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/chai2010/webp"
+)
+
+// WebPSupported 表示是否能编码 WebP。编码器基于 libwebp，需要启用 cgo 构建
+const WebPSupported = true
+
+func encodeWebP(img image.Image, quality float32) (Image, error) {
+	var buf bytes.Buffer
+	if err := webp.Encode(&buf, img, &webp.Options{Quality: quality}); err != nil {
+		return Image{}, fmt.Errorf("failed to encode WebP: %w", err)
+	}
+	return newImage(img, "image/webp", ".webp", buf.Bytes()), nil
+}
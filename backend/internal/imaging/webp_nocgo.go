@@ -0,0 +1,16 @@
+//go:build !cgo
+
+package imaging
+
+import (
+	"errors"
+	"image"
+)
+
+// WebPSupported 表示是否能编码 WebP。CGO_ENABLED=0 构建时没有编码器，
+// 上传的图片不生成 WebP 版本，WebP 原图改存为 PNG；解码不受影响
+const WebPSupported = false
+
+func encodeWebP(img image.Image, quality float32) (Image, error) {
+	return Image{}, errors.New("WebP encoding requires a cgo build")
+}
@@ -0,0 +1,120 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/util"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// MaxTOCLevel 为目录收录的最深标题级别
+const MaxTOCLevel = 4
+
+// 正文中的原始 HTML 先原样输出，再统一由 sanitizer 过滤
+var converter = goldmark.New(
+	goldmark.WithExtensions(extension.GFM, extension.Footnote),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var sanitizer = newSanitizer()
+
+func newSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// 标题锚点与脚注的跳转目标
+	p.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6", "sup", "li")
+	// 代码块的语言 class，供前端语法高亮使用
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	// 任务列表的复选框
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").Matching(regexp.MustCompile(`^$`)).OnElements("input")
+	// 脚注
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(footnote-ref|footnote-backref|footnotes)$`)).OnElements("a", "div")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|backlink|endnotes)$`)).OnElements("a", "div")
+	return p
+}
+
+// Render 把 Markdown 渲染为过滤后的 HTML，并返回由标题生成的目录。
+// 标题锚点由标题文字生成（中文转为拼音），重复时追加 -1、-2…
+func Render(source string) (string, []model.TOCEntry, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(&headingIDs{used: map[string]bool{}}))
+	doc := converter.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := converter.Renderer().Render(&buf, src, doc); err != nil {
+		return "", nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+	return sanitizer.Sanitize(buf.String()), tableOfContents(doc, src), nil
+}
+
+func tableOfContents(doc ast.Node, source []byte) []model.TOCEntry {
+	toc := []model.TOCEntry{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if heading.Level <= MaxTOCLevel {
+			id, _ := heading.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			toc = append(toc, model.TOCEntry{
+				Level: heading.Level,
+				ID:    string(idBytes),
+				Text:  headingText(heading, source),
+			})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// headingText 返回标题的纯文本，忽略强调、链接等格式
+func headingText(n ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := child.(type) {
+		case *ast.Text:
+			buf.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+// headingIDs 用 util.Slugify 生成标题锚点，默认实现会丢弃中文字符
+type headingIDs struct {
+	used map[string]bool
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := util.Slugify(string(value))
+	id := base
+	for n := 1; h.used[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	h.used[id] = true
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
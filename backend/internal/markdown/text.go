@@ -0,0 +1,129 @@
+package markdown
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 阅读速度：中日韩文字按字计，其他语言按词计
+const (
+	cjkCharsPerMinute = 300
+	wordsPerMinute    = 200
+)
+
+// Stats 统计渲染后正文的字数与预计阅读分钟数。中日韩文字每个字计为一个词，
+// 其他文字按连续的字母数字计为一个词；有内容时阅读时间至少为 1 分钟
+func Stats(renderedHTML string) (words int, minutes int) {
+	cjk, other := countWords(plainText(renderedHTML, false))
+	words = cjk + other
+	if words == 0 {
+		return 0, 0
+	}
+	minutes = int(math.Ceil(float64(cjk)/cjkCharsPerMinute + float64(other)/wordsPerMinute))
+	return words, minutes
+}
+
+// Summary 由渲染后正文的段落文字生成摘要，超过 limit 个字符时截断并追加省略号
+func Summary(renderedHTML string, limit int) string {
+	text := plainText(renderedHTML, true)
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)[:limit]
+	// 尽量在句子或单词边界截断
+	cut := len(runes)
+	for i := len(runes) - 1; i > limit*2/3; i-- {
+		if strings.ContainsRune("。！？；.!?; ", runes[i]) {
+			cut = i + 1
+			break
+		}
+	}
+	return strings.TrimSpace(string(runes[:cut])) + "…"
+}
+
+func countWords(text string) (cjk, other int) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				other++
+			}
+			inWord = true
+		case r == '\'' || r == '-' || r == '_':
+			// 单词内的撇号、连字符不拆分单词
+		default:
+			inWord = false
+		}
+	}
+	return cjk, other
+}
+
+// plainText 提取 HTML 中的文字并折叠空白。paragraphsOnly 为 true 时只取段落文字，
+// 跳过标题、代码块、表格与脚注
+func plainText(source string, paragraphsOnly bool) string {
+	nodes, err := html.ParseFragment(strings.NewReader(source), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	var walk func(n *html.Node, inParagraph bool)
+	walk = func(n *html.Node, inParagraph bool) {
+		switch n.Type {
+		case html.TextNode:
+			if inParagraph || !paragraphsOnly {
+				b.WriteString(n.Data)
+			}
+			return
+		case html.ElementNode:
+			if paragraphsOnly && (n.DataAtom == atom.Sup || hasClass(n, "footnotes")) {
+				return
+			}
+			if n.DataAtom == atom.P {
+				inParagraph = true
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, inParagraph)
+		}
+		if !inlineElements[n.DataAtom] {
+			b.WriteString(" ")
+		}
+	}
+	for _, node := range nodes {
+		walk(node, false)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+var inlineElements = map[atom.Atom]bool{
+	atom.A: true, atom.Em: true, atom.Strong: true, atom.B: true, atom.I: true,
+	atom.Code: true, atom.Del: true, atom.S: true, atom.Span: true, atom.Mark: true,
+	atom.Sub: true, atom.Sup: true, atom.U: true, atom.Small: true,
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, a := range n.Attr {
+		if a.Key == "class" {
+			for _, c := range strings.Fields(a.Val) {
+				if c == class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
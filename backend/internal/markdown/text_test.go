@@ -0,0 +1,125 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		cjk   int
+		other int
+	}{
+		{"empty", "", 0, 0},
+		{"english", "Hello, world!", 0, 2},
+		{"chinese", "你好，世界。", 4, 0},
+		{"mixed", "Go语言 is great", 2, 3},
+		{"japanese and korean", "ひらがな カタカナ 한국어", 11, 0},
+		{"apostrophes and hyphens", "don't well-known snake_case", 0, 3},
+		{"digits", "version 1.22 in 2024", 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cjk, other := countWords(tt.text)
+			if cjk != tt.cjk || other != tt.other {
+				t.Errorf("countWords(%q) = %d, %d, want %d, %d", tt.text, cjk, other, tt.cjk, tt.other)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		html    string
+		words   int
+		minutes int
+	}{
+		{"empty", "", 0, 0},
+		{"short text takes one minute", "<p>Hello world</p>", 2, 1},
+		{"code blocks are counted", "<p>Run it:</p><pre><code>go test ./...</code></pre>", 4, 1},
+		{"block elements separate words", "<h1>Title</h1><p>Body</p><ul><li>one</li><li>two</li></ul>", 4, 1},
+		{"inline elements do not split words", "<p>un<em>believ</em>able</p>", 1, 1},
+		{"english at rate", "<p>" + strings.Repeat("word ", 400) + "</p>", 400, 2},
+		{"chinese at rate", "<p>" + strings.Repeat("字", 600) + "</p>", 600, 2},
+		{"mixed rates add up", "<p>" + strings.Repeat("字", 300) + strings.Repeat(" word", 201) + "</p>", 501, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, minutes := Stats(tt.html)
+			if words != tt.words || minutes != tt.minutes {
+				t.Errorf("Stats() = %d, %d, want %d, %d", words, minutes, tt.words, tt.minutes)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		html  string
+		limit int
+		want  string
+	}{
+		{
+			name:  "paragraphs only",
+			html:  "<h1>Title</h1><p>First <strong>bold</strong> paragraph.</p><pre><code>code()</code></pre><p>Second.</p>",
+			limit: 100,
+			want:  "First bold paragraph. Second.",
+		},
+		{
+			name:  "footnotes are skipped",
+			html:  `<p>Claim<sup id="fnref:1"><a href="#fn:1" class="footnote-ref">1</a></sup> here.</p><div class="footnotes"><ol><li><p>Source.</p></li></ol></div>`,
+			limit: 100,
+			want:  "Claim here.",
+		},
+		{
+			name:  "exactly at limit is kept whole",
+			html:  "<p>" + strings.Repeat("字", 10) + "</p>",
+			limit: 10,
+			want:  strings.Repeat("字", 10),
+		},
+		{
+			name:  "one over limit is truncated",
+			html:  "<p>" + strings.Repeat("字", 11) + "</p>",
+			limit: 10,
+			want:  strings.Repeat("字", 10) + "…",
+		},
+		{
+			name:  "cut at chinese sentence end",
+			html:  "<p>第一句话写完了。第二句话还没有写完</p>",
+			limit: 10,
+			want:  "第一句话写完了。…",
+		},
+		{
+			name:  "cut at word boundary",
+			html:  "<p>The quick brown fox jumps over the lazy dog</p>",
+			limit: 22,
+			want:  "The quick brown fox…",
+		},
+		{
+			name:  "boundary too early is ignored",
+			html:  "<p>Hi. " + strings.Repeat("a", 20) + "</p>",
+			limit: 12,
+			want:  "Hi. aaaaaaaa…",
+		},
+		{
+			name:  "no paragraphs",
+			html:  "<h2>Only a heading</h2>",
+			limit: 10,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summary(tt.html, tt.limit); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
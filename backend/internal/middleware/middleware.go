@@ -12,7 +12,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func Auth() gin.HandlerFunc {
+// SessionValidator 校验 access token 中的会话（jti）是否仍然有效
+type SessionValidator interface {
+	ValidateSession(userID int, sessionID string) error
+}
+
+func Auth(sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -35,14 +40,21 @@ func Auth() gin.HandlerFunc {
 			return
 		}
 
+		if err := sessions.ValidateSession(claims.UserID, claims.ID); err != nil {
+			response.Unauthorized(c, "Session is no longer valid")
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("sessionID", claims.ID)
 		c.Next()
 	}
 }
 
-func OptionalAuth() gin.HandlerFunc {
+func OptionalAuth(sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -62,9 +74,15 @@ func OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
+		if err := sessions.ValidateSession(claims.UserID, claims.ID); err != nil {
+			c.Next()
+			return
+		}
+
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("sessionID", claims.ID)
 		c.Next()
 	}
 }
@@ -102,7 +120,7 @@ func CORS() gin.HandlerFunc {
 		}
 		
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Requested-With, X-Article-Token")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400") // 24小时预检缓存
 
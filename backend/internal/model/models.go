@@ -16,15 +16,36 @@ type User struct {
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// 文章可见性。unlisted 只能通过链接访问，不出现在列表和搜索中；private 只有管理员可见；
+// password 需要输入密码才能查看正文
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+	VisibilityPassword = "password"
+)
+
+// 文章到期（ExpiresAt）后的处理方式：unpublish 转为草稿，archive 转为不公开列出、仍可通过链接访问
+const (
+	ExpiryUnpublish = "unpublish"
+	ExpiryArchive   = "archive"
+)
+
 type Article struct {
-	ID           int        `json:"id" db:"id"`
-	Title        string     `json:"title" db:"title"`
-	Content      string     `json:"content" db:"content"`
-	Summary      string     `json:"summary" db:"summary"`
-	Tags         []string   `json:"tags" db:"tags"`
-	AuthorID     int        `json:"-" db:"author_id"`
-	Author       *User      `json:"author,omitempty"`
-	Status       string     `json:"status" db:"status"`
+	ID       int      `json:"id" db:"id"`
+	Title    string   `json:"title" db:"title"`
+	Slug     string   `json:"slug" db:"slug"`
+	Content  string   `json:"content" db:"content"`
+	Summary  string   `json:"summary" db:"summary"`
+	Tags     []string `json:"tags" db:"tags"`
+	AuthorID int      `json:"-" db:"author_id"`
+	Author   *User    `json:"author,omitempty"`
+	Status   string   `json:"status" db:"status"`
+	// Version 每次更新加 1，更新时须通过 If-Match 或 version 字段提交读取到的版本
+	Version      int        `json:"version" db:"version"`
+	CategoryID   *int       `json:"category_id" db:"category_id"`
+	Visibility   string     `json:"visibility" db:"visibility"`
+	PasswordHash string     `json:"-" db:"password_hash"`
 	ViewCount    int        `json:"view_count" db:"view_count"`
 	LikeCount    int        `json:"like_count" db:"like_count"`
 	CommentCount int        `json:"comment_count" db:"comment_count"`
@@ -32,7 +53,42 @@ type Article struct {
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 	PublishedAt  *time.Time `json:"published_at" db:"published_at"`
+	ExpiresAt    *time.Time `json:"expires_at" db:"expires_at"`
+	ExpiryAction string     `json:"expiry_action" db:"expiry_action"`
 	DeletedAt    *time.Time `json:"deleted_at" db:"deleted_at"`
+	// Pinned 的文章在列表中始终排在最前，PinnedUntil 为空表示一直置顶
+	Pinned      bool       `json:"pinned" db:"pinned"`
+	PinnedUntil *time.Time `json:"pinned_until" db:"pinned_until"`
+	Featured    bool       `json:"featured" db:"featured"`
+	// WordCount 中日韩文字按字计，ReadingTime 为预计阅读分钟数，二者在保存时由渲染后的正文计算
+	WordCount   int `json:"word_count" db:"word_count"`
+	ReadingTime int `json:"reading_time" db:"reading_time"`
+	// SummaryAuto 表示摘要由正文自动生成，正文修改时会随之重新生成
+	SummaryAuto bool `json:"summary_auto" db:"summary_auto"`
+	// Locked 表示文章受密码保护且读者尚未解锁，此时不返回正文
+	Locked bool `json:"locked,omitempty" db:"-"`
+	// Category 为主分类的名称与 slug
+	Category *ArticleCategory `json:"category,omitempty" db:"-"`
+	// Highlight 仅在搜索结果中返回
+	Highlight *ArticleHighlight `json:"highlight,omitempty" db:"-"`
+	// Series 仅在文章详情中返回
+	Series *ArticleSeries `json:"series,omitempty" db:"-"`
+	// ContentHTML 为保存时由 Content 渲染并过滤后的 HTML，TOC 为由标题生成的目录，二者仅在文章详情中返回
+	ContentHTML string     `json:"content_html,omitempty" db:"content_html"`
+	TOC         []TOCEntry `json:"toc,omitempty" db:"toc"`
+}
+
+// TOCEntry 为目录中的一个标题，ID 为正文 HTML 中对应标题的锚点
+type TOCEntry struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// ArticleHighlight 为搜索命中的标题和正文片段，命中的词用 <mark> 包裹，其余内容已做 HTML 转义
+type ArticleHighlight struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
 }
 
 type Comment struct {
@@ -57,24 +113,254 @@ type Like struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
+type RefreshToken struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	FamilyID  string     `json:"family_id" db:"family_id"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type Session struct {
+	ID         string     `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	Device     string     `json:"device"`
+	IPAddress  string     `json:"ip_address" db:"ip_address"`
+	Current    bool       `json:"current"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
 type CreateArticleRequest struct {
-	Title       string     `json:"title" binding:"required,min=1,max=200"`
+	Title string `json:"title" binding:"required,min=1,max=200"`
+	// Slug 为空时由标题生成，Summary 为空时由正文的前几段自动生成
+	Slug        string     `json:"slug" binding:"omitempty,max=100"`
 	Content     string     `json:"content" binding:"required,min=1,max=104857600"`
-	Summary     string     `json:"summary" binding:"required,min=1,max=500"`
+	Summary     string     `json:"summary" binding:"omitempty,max=500"`
 	Tags        []string   `json:"tags" binding:"max=10,dive,max=50"`
 	Status      string     `json:"status" binding:"required,oneof=draft published scheduled"`
+	CategoryID  *int       `json:"category_id" binding:"omitempty,min=0"`
 	CoverImage  *string    `json:"cover_image" binding:"omitempty,url"`
 	PublishedAt *time.Time `json:"published_at"`
+	// Visibility 默认为 public；为 password 时必须设置 Password
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public unlisted private password"`
+	Password   string `json:"password" binding:"omitempty,max=72"`
+	// ExpiresAt 必须晚于当前时间和 PublishedAt，ExpiryAction 默认为 unpublish
+	ExpiresAt    *time.Time `json:"expires_at"`
+	ExpiryAction string     `json:"expiry_action" binding:"omitempty,oneof=unpublish archive"`
 }
 
 type UpdateArticleRequest struct {
-	Title       *string    `json:"title" binding:"omitempty,min=1,max=200"`
+	Title *string `json:"title" binding:"omitempty,min=1,max=200"`
+	// Slug 未指定时，修改标题会重新生成 slug，旧 slug 重定向到新 slug
+	Slug        *string    `json:"slug" binding:"omitempty,max=100"`
 	Content     *string    `json:"content" binding:"omitempty,min=1,max=104857600"`
-	Summary     *string    `json:"summary" binding:"omitempty,min=1,max=500"`
+	Summary     *string    `json:"summary" binding:"omitempty,max=500"`
 	Tags        []string   `json:"tags" binding:"omitempty,max=10,dive,max=50"`
 	Status      *string    `json:"status" binding:"omitempty,oneof=draft published scheduled"`
 	CoverImage  *string    `json:"cover_image" binding:"omitempty,url"`
 	PublishedAt *time.Time `json:"published_at"`
+	// CategoryID 为 0 时清除分类
+	CategoryID *int `json:"category_id" binding:"omitempty,min=0"`
+	// Password 只在可见性为 password 时使用，省略或为空时保留原密码
+	Visibility *string `json:"visibility" binding:"omitempty,oneof=public unlisted private password"`
+	Password   *string `json:"password" binding:"omitempty,max=72"`
+	// ClearExpiresAt 为 true 时取消到期时间
+	ExpiresAt      *time.Time `json:"expires_at"`
+	ExpiryAction   *string    `json:"expiry_action" binding:"omitempty,oneof=unpublish archive"`
+	ClearExpiresAt bool       `json:"clear_expires_at"`
+	// Version 为编辑时读取到的版本，也可以用 If-Match 请求头提交
+	Version *int `json:"version" binding:"omitempty,min=1"`
+}
+
+// ArticleRevision 是文章某次更新之前的快照。EditorID/Changes 描述的是覆盖这个版本的那次更新：
+// 谁在什么时候（CreatedAt）修改了哪些字段
+type ArticleRevision struct {
+	ID         int       `json:"id"`
+	ArticleID  int       `json:"article_id"`
+	Revision   int       `json:"revision"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	Content    string    `json:"content,omitempty"`
+	Summary    string    `json:"summary"`
+	Tags       []string  `json:"tags"`
+	Status     string    `json:"status"`
+	CoverImage *string   `json:"cover_image"`
+	Changes    []string  `json:"changes"`
+	EditorID   *int      `json:"editor_id"`
+	EditorName string    `json:"editor_name,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	// 分类、可见性与到期设置的快照，密码只保存哈希且不返回
+	CategoryID   *int       `json:"category_id"`
+	Visibility   string     `json:"visibility"`
+	PasswordHash string     `json:"-"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	ExpiryAction string     `json:"expiry_action"`
+}
+
+// DiffLine 为行级 diff 的一行，Type 为 equal、insert 或 delete；
+// OldLine/NewLine 是该行在旧、新内容中的行号（从 1 开始，不存在时为 0）
+type DiffLine struct {
+	Type    string `json:"type"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+	Text    string `json:"text"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// RevisionDiff 比较两个版本，To 为 0 表示文章当前内容
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Fields  []FieldChange `json:"fields"`
+	Lines   []DiffLine    `json:"lines"`
+	Added   int           `json:"added"`
+	Removed int           `json:"removed"`
+}
+
+// Category 是层级分类，ParentID 为空表示顶级分类。
+// ArticleCount 为直接属于该分类的文章数，TotalCount 还包括所有子分类中的文章
+type Category struct {
+	ID           int         `json:"id"`
+	Name         string      `json:"name"`
+	Slug         string      `json:"slug"`
+	Description  string      `json:"description"`
+	ParentID     *int        `json:"parent_id"`
+	ArticleCount int         `json:"article_count"`
+	TotalCount   int         `json:"total_count"`
+	Children     []*Category `json:"children"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+type ArticleCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// CategoryRequest 创建或修改分类，Slug 为空时由名称生成；ParentID 为空表示顶级分类
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	ParentID    *int   `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+// Series 是按顺序组织的一组文章，例如分多篇的教程
+type Series struct {
+	ID          int             `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Articles    []SeriesArticle `json:"articles"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// SeriesArticle 是系列中的一篇文章，Position 为在可见文章中的序号（从 1 开始）
+type SeriesArticle struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Status      string     `json:"status"`
+	Position    int        `json:"position"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+// ArticleSeries 是文章详情中所属系列的信息，Previous/Next 为相邻的上一篇、下一篇
+type ArticleSeries struct {
+	ID       int            `json:"id"`
+	Title    string         `json:"title"`
+	Position int            `json:"position"`
+	Total    int            `json:"total"`
+	Previous *SeriesArticle `json:"previous"`
+	Next     *SeriesArticle `json:"next"`
+}
+
+type SeriesRequest struct {
+	Title       string `json:"title" binding:"required,min=1,max=200"`
+	Description string `json:"description" binding:"max=2000"`
+	// ArticleIDs 为系列中文章的完整顺序，已属于其他系列的文章会移到本系列
+	ArticleIDs []int `json:"article_ids" binding:"max=200"`
+}
+
+// CreatePreviewRequest 生成预览链接，ExpiresInHours 省略时默认 72 小时
+type CreatePreviewRequest struct {
+	ExpiresInHours int `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+// PreviewToken 是文章的签名预览令牌，持有者可以通过预览接口查看未发布的文章，过期后失效
+type PreviewToken struct {
+	ArticleID int       `json:"article_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PinArticleRequest 置顶或取消置顶文章，PinnedUntil 省略时一直置顶，取消置顶时忽略
+type PinArticleRequest struct {
+	Pinned      bool       `json:"pinned"`
+	PinnedUntil *time.Time `json:"pinned_until"`
+}
+
+type FeatureArticleRequest struct {
+	Featured bool `json:"featured"`
+}
+
+type UnlockArticleRequest struct {
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+// ArticleAccessToken 是输入密码后获得的访问令牌，在 X-Article-Token 请求头中提交即可查看受密码保护的文章
+type ArticleAccessToken struct {
+	ArticleID int       `json:"article_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ArticleAutosave 是用户编辑中的工作稿，与文章的正式内容分开保存，每个用户每篇文章一份。
+// ArticleID 为 0 表示尚未创建的新文章；BaseVersion 为开始编辑时文章的版本，
+// Stale 表示此后文章已被修改，提交时会产生版本冲突
+type ArticleAutosave struct {
+	ID          int       `json:"id"`
+	ArticleID   int       `json:"article_id"`
+	UserID      int       `json:"user_id"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Summary     string    `json:"summary"`
+	Tags        []string  `json:"tags"`
+	CoverImage  *string   `json:"cover_image"`
+	BaseVersion int       `json:"base_version"`
+	Stale       bool      `json:"stale"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AutosaveRequest 保存编辑中的内容，字段可以不完整。
+// BaseVersion 省略时沿用已有自动保存的版本，首次保存时取文章当前版本
+type AutosaveRequest struct {
+	Title       string   `json:"title" binding:"max=200"`
+	Content     string   `json:"content" binding:"max=104857600"`
+	Summary     string   `json:"summary" binding:"max=500"`
+	Tags        []string `json:"tags" binding:"max=10,dive,max=50"`
+	CoverImage  *string  `json:"cover_image" binding:"omitempty,url"`
+	BaseVersion int      `json:"base_version" binding:"min=0"`
+}
+
+// PromoteAutosaveRequest 把自动保存提交为文章的正式内容。新文章默认保存为草稿，
+// 已有文章默认保持原状态；Version 省略时用自动保存的 BaseVersion 检查冲突
+type PromoteAutosaveRequest struct {
+	Status      string     `json:"status" binding:"omitempty,oneof=draft published scheduled"`
+	PublishedAt *time.Time `json:"published_at"`
+	Version     *int       `json:"version" binding:"omitempty,min=1"`
 }
 
 type CreateCommentRequest struct {
@@ -90,18 +376,37 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token                 string    `json:"token"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+	User                  User      `json:"user"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type SearchParams struct {
-	Keyword       string `form:"keyword"`
-	Tags          string `form:"tags"`
+	Keyword string `form:"keyword"`
+	// Tags 为逗号分隔的标签；名称中含逗号的标签用可重复的 tag 参数
+	Tags string   `form:"tags"`
+	Tag  []string `form:"tag"`
+	// Author 为作者用户名，Year/Month 按发布时间（未发布时为创建时间）筛选
+	Author        string `form:"author"`
+	Year          int    `form:"year" binding:"omitempty,min=1970,max=9999"`
+	Month         int    `form:"month" binding:"omitempty,min=1,max=12"`
 	Page          int    `form:"page,default=1"`
 	PageSize      int    `form:"page_size,default=10"`
-	SortBy        string `form:"sort_by,default=created_at"`
+	SortBy        string `form:"sort_by,default=created_at"` // 搜索时可用 relevance
 	SortOrder     string `form:"sort_order,default=desc"`
 	IncludeDrafts bool   `form:"include_drafts,default=false"`
+	// Trashed 为 true 时只列出回收站中的文章，由回收站接口设置
+	Trashed bool `form:"-"`
+	// CategoryID/Category（slug）按分类筛选，包括其所有子分类
+	CategoryID int    `form:"category_id" binding:"omitempty,min=1"`
+	Category   string `form:"category"`
+	// Featured 为 true 时只列出精选文章
+	Featured bool `form:"featured"`
 }
 
 type ArticleListResponse struct {
@@ -109,6 +414,45 @@ type ArticleListResponse struct {
 	Total    int       `json:"total"`
 	Page     int       `json:"page"`
 	PageSize int       `json:"page_size"`
+	// Facets 仅在搜索结果中返回
+	Facets *SearchFacets `json:"facets,omitempty"`
+}
+
+// FacetCount 为某个筛选值及符合当前查询的文章数，Value 可直接作为对应的查询参数
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type SearchFacets struct {
+	Tags    []FacetCount `json:"tags"`
+	Authors []FacetCount `json:"authors"`
+	Years   []FacetCount `json:"years"`
+	// Months 的 Value 格式为 YYYY-MM
+	Months []FacetCount `json:"months"`
+}
+
+type TagCount struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type Tag struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	ArticleCount   int       `json:"article_count"`
+	PublishedCount int       `json:"published_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type MergeTagsRequest struct {
+	SourceIDs []int `json:"source_ids" binding:"required,min=1"`
+	TargetID  int   `json:"target_id" binding:"required"`
 }
 
 type CommentListResponse struct {
@@ -117,3 +461,101 @@ type CommentListResponse struct {
 	Page     int       `json:"page"`
 	PageSize int       `json:"page_size"`
 }
+
+type ImportOptions struct {
+	DryRun     bool   `form:"dry_run"`
+	OnConflict string `form:"on_conflict,default=skip" binding:"omitempty,oneof=skip overwrite rename"`
+}
+
+type ImportResult struct {
+	Source    string `json:"source"`
+	Title     string `json:"title"`
+	Action    string `json:"action"`
+	ArticleID int    `json:"article_id,omitempty"`
+	Author    string `json:"author,omitempty"`
+	Comments  int    `json:"comments,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun      bool           `json:"dry_run"`
+	OnConflict  string         `json:"on_conflict"`
+	Total       int            `json:"total"`
+	Created     int            `json:"created"`
+	Overwritten int            `json:"overwritten"`
+	Renamed     int            `json:"renamed"`
+	Skipped     int            `json:"skipped"`
+	Failed      int            `json:"failed"`
+	Comments    int            `json:"comments"`
+	Items       []ImportResult `json:"items"`
+}
+
+type ImageVariant struct {
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	URL     string `json:"url"`
+	WebPURL string `json:"webp_url,omitempty"`
+}
+
+// UploadedImage 为上传结果，SrcSet/WebPSrcSet 可直接用于 <img srcset> 与 <source type="image/webp">
+type UploadedImage struct {
+	MediaID int `json:"media_id,omitempty"`
+	// Duplicate 为 true 表示内容与已有媒体相同，直接复用了已有文件
+	Duplicate  bool           `json:"duplicate,omitempty"`
+	URL        string         `json:"url"`
+	WebPURL    string         `json:"webp_url,omitempty"`
+	Width      int            `json:"width"`
+	Height     int            `json:"height"`
+	Variants   []ImageVariant `json:"variants"`
+	SrcSet     string         `json:"srcset,omitempty"`
+	WebPSrcSet string         `json:"webp_srcset,omitempty"`
+}
+
+// MediaFile 为媒体在存储中的一个缩略图文件
+type MediaFile struct {
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Key     string `json:"key"`
+	WebPKey string `json:"webp_key,omitempty"`
+}
+
+type Media struct {
+	ID           int         `json:"id"`
+	FileName     string      `json:"filename"`
+	WebPFileName string      `json:"webp_filename,omitempty"`
+	OriginalName string      `json:"original_name"`
+	MimeType     string      `json:"mime_type"`
+	Size         int64       `json:"size"`
+	Width        int         `json:"width"`
+	Height       int         `json:"height"`
+	SHA256       string      `json:"sha256"`
+	UploaderID   *int        `json:"uploader_id"`
+	UploaderName string      `json:"uploader_name,omitempty"`
+	AltText      string      `json:"alt_text"`
+	Files        []MediaFile `json:"-"`
+	// 以下字段由存储后端生成，不入库
+	URL        string         `json:"url"`
+	WebPURL    string         `json:"webp_url,omitempty"`
+	Variants   []ImageVariant `json:"variants"`
+	Referenced *bool          `json:"referenced,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type MediaSearchParams struct {
+	Keyword  string `form:"keyword"`
+	MimeType string `form:"mime_type"`
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"page_size,default=20"`
+}
+
+type MediaListResponse struct {
+	Media    []Media `json:"media"`
+	Total    int     `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"page_size"`
+}
+
+type UpdateMediaRequest struct {
+	AltText string `json:"alt_text" binding:"max=500"`
+}
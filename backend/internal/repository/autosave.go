@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"pea-blog-backend/internal/model"
+)
+
+// SaveAutosave 写入或覆盖用户对某篇文章的自动保存
+func (r *ArticleRepository) SaveAutosave(autosave *model.ArticleAutosave) error {
+	tags, err := json.Marshal(normalizeTags(autosave.Tags))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO article_autosaves (article_id, user_id, title, content, summary, tags, cover_image, base_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, article_id) DO UPDATE SET
+			title = excluded.title,
+			content = excluded.content,
+			summary = excluded.summary,
+			tags = excluded.tags,
+			cover_image = excluded.cover_image,
+			base_version = excluded.base_version,
+			updated_at = CURRENT_TIMESTAMP
+	`,
+		autosave.ArticleID, autosave.UserID, autosave.Title, autosave.Content, autosave.Summary,
+		string(tags), autosave.CoverImage, autosave.BaseVersion,
+	)
+	return err
+}
+
+func (r *ArticleRepository) GetAutosave(userID, articleID int) (*model.ArticleAutosave, error) {
+	autosave := &model.ArticleAutosave{}
+	var tags string
+	err := r.db.QueryRow(`
+		SELECT id, article_id, user_id, title, content, summary, tags, cover_image, base_version, created_at, updated_at
+		FROM article_autosaves
+		WHERE user_id = ? AND article_id = ?
+	`, userID, articleID).Scan(
+		&autosave.ID, &autosave.ArticleID, &autosave.UserID, &autosave.Title, &autosave.Content,
+		&autosave.Summary, &tags, &autosave.CoverImage, &autosave.BaseVersion,
+		&autosave.CreatedAt, &autosave.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("autosave not found")
+		}
+		return nil, err
+	}
+
+	autosave.Tags = []string{}
+	if tags != "" {
+		if err := json.Unmarshal([]byte(tags), &autosave.Tags); err != nil {
+			return nil, fmt.Errorf("invalid autosave tags: %w", err)
+		}
+	}
+	return autosave, nil
+}
+
+func (r *ArticleRepository) DeleteAutosave(userID, articleID int) error {
+	result, err := r.db.Exec("DELETE FROM article_autosaves WHERE user_id = ? AND article_id = ?", userID, articleID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("autosave not found")
+	}
+	return nil
+}
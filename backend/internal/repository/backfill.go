@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/util"
+)
+
+// Backfill 在 database.Migrate 之后为已有文章补齐依赖应用逻辑生成的列。
+// 这些步骤需要 internal 包中的 slug 规则和 Markdown 渲染，因此不放在 pkg/database 中
+func Backfill(db *sql.DB) error {
+	r := NewArticleRepository(db)
+	if err := r.backfillSlugs(); err != nil {
+		return err
+	}
+	return r.backfillContentHTML()
+}
+
+// backfillSlugs 为还没有 slug 的文章按标题生成唯一 slug
+func (r *ArticleRepository) backfillSlugs() error {
+	used := make(map[string]bool)
+	type pending struct {
+		id    int
+		title string
+	}
+	var missing []pending
+	rows, err := r.db.Query("SELECT id, title, COALESCE(slug, '') FROM articles ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("failed to read article slugs: %w", err)
+	}
+	for rows.Next() {
+		var p pending
+		var slug string
+		if err := rows.Scan(&p.id, &p.title, &slug); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read article slugs: %w", err)
+		}
+		if slug == "" {
+			missing = append(missing, p)
+		} else {
+			used[slug] = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read article slugs: %w", err)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, p := range missing {
+		base := util.Slugify(p.title)
+		slug := base
+		for n := 2; used[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[slug] = true
+		q := queryArgs{dbType: r.dbType}
+		if _, err := tx.Exec("UPDATE articles SET slug = "+q.add(slug)+" WHERE id = "+q.add(p.id), q.args...); err != nil {
+			return fmt.Errorf("failed to generate article slugs: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// backfillContentHTML 为尚未渲染的已有文章生成正文 HTML、目录与字数。
+// 只按 content_html 为空判断：正文没有文字的文章字数本来就是 0，不能据此在每次启动时重复渲染
+func (r *ArticleRepository) backfillContentHTML() error {
+	var missing []model.Article
+	rows, err := r.db.Query("SELECT id, content FROM articles WHERE content_html = '' AND content != ''")
+	if err != nil {
+		return fmt.Errorf("failed to read article content: %w", err)
+	}
+	for rows.Next() {
+		var article model.Article
+		if err := rows.Scan(&article.ID, &article.Content); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read article content: %w", err)
+		}
+		missing = append(missing, article)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read article content: %w", err)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range missing {
+		article := &missing[i]
+		tocJSON, err := renderContent(article)
+		if err != nil {
+			return fmt.Errorf("failed to render article %d: %w", article.ID, err)
+		}
+		q := queryArgs{dbType: r.dbType}
+		query := "UPDATE articles SET content_html = " + q.add(article.ContentHTML) + ", toc = " + q.add(tocJSON) +
+			", word_count = " + q.add(article.WordCount) + ", reading_time = " + q.add(article.ReadingTime) +
+			" WHERE id = " + q.add(article.ID)
+		if _, err := tx.Exec(query, q.args...); err != nil {
+			return fmt.Errorf("failed to save rendered content: %w", err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,35 @@
+package repository
+
+import "testing"
+
+func TestBackfillContentHTML(t *testing.T) {
+	r := newTestArticleRepository(t)
+	pending := createTestArticle(t, r, "Pending")
+	rendered := createTestArticle(t, r, "Rendered")
+	if _, err := r.db.Exec("UPDATE articles SET content_html = '', word_count = 0 WHERE id = ?", pending.ID); err != nil {
+		t.Fatalf("reset pending: %v", err)
+	}
+	// 已渲染但字数为 0 的文章（例如只有图片）不应重新渲染
+	if _, err := r.db.Exec("UPDATE articles SET content_html = '<p>kept</p>', word_count = 0 WHERE id = ?", rendered.ID); err != nil {
+		t.Fatalf("reset rendered: %v", err)
+	}
+
+	if err := Backfill(r.db); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+
+	var html string
+	var words int
+	if err := r.db.QueryRow("SELECT content_html, word_count FROM articles WHERE id = ?", pending.ID).Scan(&html, &words); err != nil {
+		t.Fatalf("load pending: %v", err)
+	}
+	if html == "" || words == 0 {
+		t.Errorf("pending article = %q, %d words, want rendered content", html, words)
+	}
+	if err := r.db.QueryRow("SELECT content_html FROM articles WHERE id = ?", rendered.ID).Scan(&html); err != nil {
+		t.Fatalf("load rendered: %v", err)
+	}
+	if html != "<p>kept</p>" {
+		t.Errorf("rendered article html = %q, want it left alone", html)
+	}
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/util"
+)
+
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetAll 返回全部分类（不组装层级），ArticleCount 为直接属于该分类的文章数。
+// publishedOnly 为 true 时只统计公开列出的已发布文章，回收站中的文章不计入
+func (r *CategoryRepository) GetAll(publishedOnly bool) ([]model.Category, error) {
+	countFilter := ""
+	if publishedOnly {
+		countFilter = " AND a.status = 'published' AND " + listedVisibility
+	}
+	rows, err := r.db.Query(`
+		SELECT c.id, c.name, c.slug, c.description, c.parent_id, c.created_at,
+			(SELECT COUNT(*) FROM articles a WHERE a.category_id = c.id AND a.deleted_at IS NULL` + countFilter + `)
+		FROM categories c
+		ORDER BY c.name, c.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []model.Category{}
+	for rows.Next() {
+		var category model.Category
+		err := rows.Scan(&category.ID, &category.Name, &category.Slug, &category.Description,
+			&category.ParentID, &category.CreatedAt, &category.ArticleCount)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+func (r *CategoryRepository) GetByID(id int) (*model.Category, error) {
+	category := &model.Category{}
+	err := r.db.QueryRow(
+		"SELECT id, name, slug, description, parent_id, created_at FROM categories WHERE id = ?", id,
+	).Scan(&category.ID, &category.Name, &category.Slug, &category.Description, &category.ParentID, &category.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// uniqueCategorySlug 在 slug 已被其他分类占用时追加 -2、-3… 后缀
+func (r *CategoryRepository) uniqueCategorySlug(q execer, name, slug string, categoryID int) (string, error) {
+	base := util.Slugify(slug)
+	if slug == "" {
+		base = util.Slugify(name)
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		var count int
+		err := q.QueryRow("SELECT COUNT(*) FROM categories WHERE slug = ? AND id != ?", candidate, categoryID).Scan(&count)
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+func (r *CategoryRepository) Create(category *model.Category) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if category.Slug, err = r.uniqueCategorySlug(tx, category.Name, category.Slug, 0); err != nil {
+		return err
+	}
+	result, err := tx.Exec(
+		"INSERT INTO categories (name, slug, description, parent_id) VALUES (?, ?, ?, ?)",
+		category.Name, category.Slug, category.Description, category.ParentID,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	category.ID = int(id)
+	return tx.Commit()
+}
+
+func (r *CategoryRepository) Update(category *model.Category) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if category.Slug, err = r.uniqueCategorySlug(tx, category.Name, category.Slug, category.ID); err != nil {
+		return err
+	}
+	result, err := tx.Exec(
+		"UPDATE categories SET name = ?, slug = ?, description = ?, parent_id = ? WHERE id = ?",
+		category.Name, category.Slug, category.Description, category.ParentID, category.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("category not found")
+	}
+	return tx.Commit()
+}
+
+// Delete 删除分类，其子分类和文章移到上一级分类（顶级分类的文章变为未分类）
+func (r *CategoryRepository) Delete(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID *int
+	err = tx.QueryRow("SELECT parent_id FROM categories WHERE id = ?", id).Scan(&parentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("category not found")
+		}
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE categories SET parent_id = ? WHERE parent_id = ?", parentID, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE articles SET category_id = ? WHERE category_id = ?", parentID, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM categories WHERE id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getArticleCategory 返回文章详情中的分类信息，分类不存在时返回 nil
+func getArticleCategory(db *sql.DB, categoryID int) (*model.ArticleCategory, error) {
+	category := &model.ArticleCategory{}
+	err := db.QueryRow("SELECT id, name, slug FROM categories WHERE id = ?", categoryID).
+		Scan(&category.ID, &category.Name, &category.Slug)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// loadCategories 填充文章列表的分类信息。分类数量通常很少，直接一次读出全部
+func (r *ArticleRepository) loadCategories(articles []model.Article) error {
+	hasCategory := false
+	for i := range articles {
+		if articles[i].CategoryID != nil {
+			hasCategory = true
+			break
+		}
+	}
+	if !hasCategory {
+		return nil
+	}
+
+	rows, err := r.db.Query("SELECT id, name, slug FROM categories")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	categories := map[int]*model.ArticleCategory{}
+	for rows.Next() {
+		category := &model.ArticleCategory{}
+		if err := rows.Scan(&category.ID, &category.Name, &category.Slug); err != nil {
+			return err
+		}
+		categories[category.ID] = category
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range articles {
+		if articles[i].CategoryID != nil {
+			articles[i].Category = categories[*articles[i].CategoryID]
+		}
+	}
+	return nil
+}
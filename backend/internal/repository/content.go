@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"pea-blog-backend/internal/markdown"
+	"pea-blog-backend/internal/model"
+)
+
+// renderContent 在保存前渲染正文的 HTML 与目录并统计字数，返回目录的 JSON 用于写入 toc 列
+func renderContent(article *model.Article) (string, error) {
+	html, toc, err := markdown.Render(article.Content)
+	if err != nil {
+		return "", err
+	}
+	tocJSON, err := json.Marshal(toc)
+	if err != nil {
+		return "", err
+	}
+	article.ContentHTML = html
+	article.TOC = toc
+	article.WordCount, article.ReadingTime = markdown.Stats(html)
+	return string(tocJSON), nil
+}
+
+// parseTOC 解析 toc 列，旧数据为空时返回空目录
+func parseTOC(tocJSON string) ([]model.TOCEntry, error) {
+	toc := []model.TOCEntry{}
+	if tocJSON == "" {
+		return toc, nil
+	}
+	if err := json.Unmarshal([]byte(tocJSON), &toc); err != nil {
+		return nil, err
+	}
+	return toc, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import "testing"
+
+func TestMediaIsReferenced(t *testing.T) {
+	articles := newTestArticleRepository(t)
+	media := NewMediaRepository(articles.db)
+
+	article := createTestArticle(t, articles, "Photos")
+	// 正文只引用了缩略图的 WebP 版本
+	if _, err := articles.db.Exec("UPDATE articles SET content = ? WHERE id = ?", "![](/uploads/photo-320w.webp)", article.ID); err != nil {
+		t.Fatalf("set content: %v", err)
+	}
+	if _, err := articles.db.Exec("UPDATE users SET avatar = ? WHERE id = 1", "/uploads/avatar.png"); err != nil {
+		t.Fatalf("set avatar: %v", err)
+	}
+
+	tests := map[string]bool{
+		"photo.jpg":  true,
+		"avatar.png": true,
+		"unused.png": false,
+	}
+	for fileName, want := range tests {
+		got, err := media.IsReferenced(fileName)
+		if err != nil {
+			t.Fatalf("IsReferenced(%q): %v", fileName, err)
+		}
+		if got != want {
+			t.Errorf("IsReferenced(%q) = %v, want %v", fileName, got, want)
+		}
+	}
+}
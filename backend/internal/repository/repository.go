@@ -2,14 +2,44 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
 	"pea-blog-backend/internal/model"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
-	"github.com/lib/pq"
 )
 
+var ErrRefreshTokenReused = errors.New("refresh token already used")
+
+// ErrVersionConflict 表示文章在读取之后已被其他请求修改
+var ErrVersionConflict = errors.New("version conflict")
+
+// execer 由 *sql.DB 和 *sql.Tx 共同实现，使写操作既可单独执行也可放入事务
+// listedVisibility 筛选出现在公开列表、搜索和统计中的文章：不公开列出和私密的文章除外
+const listedVisibility = "a.visibility IN ('public', 'password')"
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// requireActiveArticle 检查文章存在且不在回收站中
+func requireActiveArticle(q execer, articleID int) error {
+	var count int
+	if err := q.QueryRow("SELECT COUNT(*) FROM articles WHERE id = ? AND deleted_at IS NULL", articleID).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("article not found")
+	}
+	return nil
+}
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -118,6 +148,79 @@ func (r *UserRepository) CreateGuestUser(fingerprint string) (*model.User, error
 	return r.GetByID(user.ID)
 }
 
+// FindOrCreateImportedUserTx 为导入的评论作者查找或创建访客用户。
+// 邮箱与已有访客相同时直接复用，否则按 "import:" 前缀的指纹去重；
+// 邮箱属于注册用户或管理员时不复用该账号，另建使用占位邮箱的访客，避免评论被冒名归到他们名下。
+func (r *UserRepository) FindOrCreateImportedUserTx(tx *sql.Tx, name, email string) (int, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	name = strings.TrimSpace(name)
+
+	var id int
+	emailTaken := false
+	if email != "" {
+		var role string
+		err := tx.QueryRow("SELECT id, role FROM users WHERE email = ?", email).Scan(&id, &role)
+		if err == nil {
+			if role == "guest" {
+				return id, nil
+			}
+			emailTaken = true
+		} else if err != sql.ErrNoRows {
+			return 0, err
+		}
+	}
+
+	fingerprint := "import:" + email
+	if email == "" {
+		fingerprint = "import:name:" + name
+	}
+	err := tx.QueryRow("SELECT id FROM users WHERE fingerprint = ?", fingerprint).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	base := []rune(name)
+	if len(base) == 0 {
+		base = []rune("guest")
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	username := string(base)
+	for n := 2; ; n++ {
+		var exists int
+		err := tx.QueryRow("SELECT COUNT(*) FROM users WHERE username = ?", username).Scan(&exists)
+		if err != nil {
+			return 0, err
+		}
+		if exists == 0 {
+			break
+		}
+		username = fmt.Sprintf("%s-%d", string(base), n)
+	}
+
+	if email == "" || emailTaken {
+		email = strings.ReplaceAll(gofakeit.UUID(), "-", "") + "@imported.invalid"
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO users (username, email, password_hash, role, fingerprint)
+		VALUES (?, ?, ?, ?, ?)
+	`, username, email, "", "guest", fingerprint)
+	if err != nil {
+		return 0, err
+	}
+
+	newID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(newID), nil
+}
+
 type ArticleRepository struct {
 	db     *sql.DB
 	dbType string
@@ -134,6 +237,21 @@ func NewArticleRepository(db *sql.DB) *ArticleRepository {
 	return &ArticleRepository{db: db, dbType: dbType}
 }
 
+// queryArgs 按方言生成占位符（postgres 为 $N，sqlite 为 ?），
+// SQL 片段必须按出现在语句中的顺序调用 add
+type queryArgs struct {
+	dbType string
+	args   []interface{}
+}
+
+func (q *queryArgs) add(value interface{}) string {
+	q.args = append(q.args, value)
+	if q.dbType == "postgres" {
+		return fmt.Sprintf("$%d", len(q.args))
+	}
+	return "?"
+}
+
 func (r *ArticleRepository) GetAll(params model.SearchParams) ([]model.Article, int, error) {
 	var articles []model.Article
 	var totalCount int
@@ -145,6 +263,8 @@ func (r *ArticleRepository) GetAll(params model.SearchParams) ([]model.Article,
 		"view_count": true,
 		"like_count": true,
 		"title":      true,
+		"relevance":  true,
+		"deleted_at": true,
 	}
 	validSortOrders := map[string]bool{
 		"asc":  true,
@@ -154,14 +274,21 @@ func (r *ArticleRepository) GetAll(params model.SearchParams) ([]model.Article,
 	if !validSortFields[params.SortBy] {
 		params.SortBy = "created_at" // 默认安全值
 	}
+	if params.SortBy == "relevance" && params.Keyword == "" {
+		params.SortBy = "created_at"
+	}
+	if params.SortBy == "deleted_at" && !params.Trashed {
+		params.SortBy = "created_at"
+	}
 	if !validSortOrders[strings.ToLower(params.SortOrder)] {
 		params.SortOrder = "desc" // 默认安全值
 	}
 
 	baseQuery := `
-		SELECT a.id, a.title, a.content, a.summary, a.tags, a.author_id, a.status,
-			   a.view_count, a.like_count, a.comment_count, a.cover_image,
-			   a.created_at, a.updated_at,
+		SELECT a.id, a.title, a.slug, a.content, a.summary, a.summary_auto, a.author_id, a.status, a.version, a.category_id, a.visibility, a.password_hash, a.expires_at, a.expiry_action,
+			   a.pinned, a.pinned_until, a.featured,
+			   a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time, a.cover_image,
+			   a.created_at, a.updated_at, a.published_at, a.deleted_at,
 			   u.id, u.username, u.email, u.avatar, u.role, u.created_at, u.updated_at
 		FROM articles a
 		JOIN users u ON a.author_id = u.id
@@ -169,46 +296,8 @@ func (r *ArticleRepository) GetAll(params model.SearchParams) ([]model.Article,
 
 	countQuery := `SELECT COUNT(*) FROM articles a`
 
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	if params.Keyword != "" {
-		if r.dbType == "postgres" {
-			conditions = append(conditions, fmt.Sprintf("(a.title ILIKE $%d OR a.content ILIKE $%d OR a.summary ILIKE $%d)", argIndex, argIndex+1, argIndex+2))
-		} else {
-			conditions = append(conditions, "(a.title LIKE ? OR a.content LIKE ? OR a.summary LIKE ?)")
-		}
-		keyword := "%" + params.Keyword + "%"
-		args = append(args, keyword, keyword, keyword)
-		argIndex += 3
-	}
-
-	if params.Tags != "" {
-		tags := strings.Split(params.Tags, ",")
-		if r.dbType == "postgres" {
-			conditions = append(conditions, fmt.Sprintf("a.tags && $%d", argIndex))
-			args = append(args, pq.Array(tags))
-		} else {
-			// For SQLite, use simple string matching (simplified approach)
-			for _, tag := range tags {
-				if r.dbType == "postgres" {
-					conditions = append(conditions, fmt.Sprintf("a.tags LIKE $%d", argIndex))
-				} else {
-					conditions = append(conditions, "a.tags LIKE ?")
-				}
-				args = append(args, "%"+tag+"%")
-				argIndex++
-			}
-		}
-		if r.dbType == "postgres" {
-			argIndex++
-		}
-	}
-
-	if !params.IncludeDrafts {
-		conditions = append(conditions, "a.status = 'published'")
-	}
+	q := &queryArgs{dbType: r.dbType}
+	conditions, search := r.filter(params, q)
 
 	if len(conditions) > 0 {
 		whereClause := " WHERE " + strings.Join(conditions, " AND ")
@@ -216,23 +305,25 @@ func (r *ArticleRepository) GetAll(params model.SearchParams) ([]model.Article,
 		countQuery += whereClause
 	}
 
-	err := r.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err := r.db.QueryRow(countQuery, q.args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	orderBy := fmt.Sprintf(" ORDER BY a.%s %s", params.SortBy, strings.ToUpper(params.SortOrder))
-	baseQuery += orderBy
-
-	offset := (params.Page - 1) * params.PageSize
-	if r.dbType == "postgres" {
-		baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	if params.SortBy == "relevance" {
+		baseQuery += " ORDER BY " + search.rank(q) + " DESC, a.created_at DESC"
+	} else if params.Trashed {
+		baseQuery += fmt.Sprintf(" ORDER BY a.%s %s", params.SortBy, strings.ToUpper(params.SortOrder))
 	} else {
-		baseQuery += " LIMIT ? OFFSET ?"
+		// 置顶未过期的文章排在最前，其余按指定字段排序
+		baseQuery += fmt.Sprintf(" ORDER BY CASE WHEN a.pinned = TRUE AND (a.pinned_until IS NULL OR a.pinned_until > %s) THEN 0 ELSE 1 END, a.%s %s",
+			q.add(r.timeArg(time.Now())), params.SortBy, strings.ToUpper(params.SortOrder))
 	}
-	args = append(args, params.PageSize, offset)
 
-	rows, err := r.db.Query(baseQuery, args...)
+	offset := (params.Page - 1) * params.PageSize
+	baseQuery += " LIMIT " + q.add(params.PageSize) + " OFFSET " + q.add(offset)
+
+	rows, err := r.db.Query(baseQuery, q.args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -241,13 +332,13 @@ func (r *ArticleRepository) GetAll(params model.SearchParams) ([]model.Article,
 	for rows.Next() {
 		var article model.Article
 		var author model.User
-		var tagsStr string
 
 		err := rows.Scan(
-			&article.ID, &article.Title, &article.Content, &article.Summary,
-			&tagsStr, &article.AuthorID, &article.Status,
-			&article.ViewCount, &article.LikeCount, &article.CommentCount,
-			&article.CoverImage, &article.CreatedAt, &article.UpdatedAt,
+			&article.ID, &article.Title, &article.Slug, &article.Content, &article.Summary, &article.SummaryAuto,
+			&article.AuthorID, &article.Status, &article.Version, &article.CategoryID, &article.Visibility, &article.PasswordHash, &article.ExpiresAt, &article.ExpiryAction,
+			&article.Pinned, &article.PinnedUntil, &article.Featured,
+			&article.ViewCount, &article.LikeCount, &article.CommentCount, &article.WordCount, &article.ReadingTime,
+			&article.CoverImage, &article.CreatedAt, &article.UpdatedAt, &article.PublishedAt, &article.DeletedAt,
 			&author.ID, &author.Username, &author.Email, &author.Avatar,
 			&author.Role, &author.CreatedAt, &author.UpdatedAt,
 		)
@@ -255,75 +346,226 @@ func (r *ArticleRepository) GetAll(params model.SearchParams) ([]model.Article,
 			return nil, 0, err
 		}
 
-		// Parse tags
-		if r.dbType == "postgres" {
-			if err := pq.Array(&article.Tags).Scan(tagsStr); err != nil {
-				article.Tags = []string{}
-			}
-		} else {
-			// For SQLite, parse comma-separated string
-			if tagsStr != "" {
-				article.Tags = strings.Split(tagsStr, ",")
-			} else {
-				article.Tags = []string{}
-			}
-		}
-
 		article.Author = &author
 		articles = append(articles, article)
 	}
 
+	if err := r.loadTags(articles); err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadCategories(articles); err != nil {
+		return nil, 0, err
+	}
+
 	return articles, totalCount, nil
 }
 
-func (r *ArticleRepository) GetByID(id int) (*model.Article, error) {
-	article := &model.Article{}
-	author := &model.User{}
-	var tagsStr string
+// filter 生成文章列表、搜索与分面统计共用的 WHERE 条件（只引用 articles a）
+func (r *ArticleRepository) filter(params model.SearchParams, q *queryArgs) ([]string, *searchQuery) {
+	var conditions []string
 
-	query := `
-		SELECT a.id, a.title, a.content, a.summary, a.tags, a.author_id, a.status,
-			   a.view_count, a.like_count, a.comment_count, a.cover_image,
-			   a.created_at, a.updated_at,
-			   u.id, u.username, u.email, u.avatar, u.role, u.created_at, u.updated_at
+	// 回收站中的文章只出现在回收站列表
+	if params.Trashed {
+		conditions = append(conditions, "a.deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "a.deleted_at IS NULL")
+	}
+
+	var search *searchQuery
+	if params.Keyword != "" {
+		search = r.buildSearch(params.Keyword)
+		conditions = append(conditions, search.where(q)...)
+	}
+
+	// 标签精确匹配，文章带有任一指定标签即命中
+	if tags := normalizeTags(append(strings.Split(params.Tags, ","), params.Tag...)); len(tags) > 0 {
+		placeholders := make([]string, len(tags))
+		for i, tag := range tags {
+			placeholders[i] = q.add(tag)
+		}
+		conditions = append(conditions, `a.id IN (
+			SELECT at.article_id FROM article_tags at JOIN tags t ON t.id = at.tag_id
+			WHERE t.name IN (`+strings.Join(placeholders, ", ")+`))`)
+	}
+
+	// 分类筛选包括所有子分类
+	if params.CategoryID > 0 || params.Category != "" {
+		seed := "slug = "
+		if params.CategoryID > 0 {
+			seed = "id = " + q.add(params.CategoryID)
+		} else {
+			seed += q.add(params.Category)
+		}
+		conditions = append(conditions, `a.category_id IN (
+			WITH RECURSIVE subtree(id) AS (
+				SELECT id FROM categories WHERE `+seed+`
+				UNION
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT id FROM subtree)`)
+	}
+
+	if params.Author != "" {
+		conditions = append(conditions, "a.author_id IN (SELECT id FROM users WHERE username = "+q.add(params.Author)+")")
+	}
+
+	if params.Year > 0 {
+		start := time.Date(params.Year, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := start.AddDate(1, 0, 0)
+		if params.Month > 0 {
+			start = time.Date(params.Year, time.Month(params.Month), 1, 0, 0, 0, 0, time.UTC)
+			end = start.AddDate(0, 1, 0)
+		}
+		conditions = append(conditions, "COALESCE(a.published_at, a.created_at) >= "+q.add(r.timeArg(start))+
+			" AND COALESCE(a.published_at, a.created_at) < "+q.add(r.timeArg(end)))
+	}
+
+	if params.Featured {
+		conditions = append(conditions, "a.featured = TRUE")
+	}
+
+	if !params.IncludeDrafts {
+		conditions = append(conditions, "a.status = 'published'", listedVisibility)
+	}
+
+	return conditions, search
+}
+
+// timeArg 返回用于比较的时间参数。SQLite 中时间以文本保存，
+// 统一用 "YYYY-MM-DD HH:MM:SS" 前缀按字典序比较
+func (r *ArticleRepository) timeArg(t time.Time) interface{} {
+	if r.dbType == "postgres" {
+		return t
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// GetFacets 统计符合当前查询的文章在标签、作者、发布年月上的分布
+func (r *ArticleRepository) GetFacets(params model.SearchParams) (*model.SearchFacets, error) {
+	q := &queryArgs{dbType: r.dbType}
+	conditions, _ := r.filter(params, q)
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	authors, err := r.facetQuery(`
+		SELECT u.username, COUNT(*)
 		FROM articles a
-		JOIN users u ON a.author_id = u.id
-		WHERE a.id = ?
-	`
+		JOIN users u ON a.author_id = u.id`+where+`
+		GROUP BY u.username`, q.args)
+	if err != nil {
+		return nil, err
+	}
 
-	row := r.db.QueryRow(query, id)
-	err := row.Scan(
-		&article.ID, &article.Title, &article.Content, &article.Summary,
-		&tagsStr, &article.AuthorID, &article.Status,
-		&article.ViewCount, &article.LikeCount, &article.CommentCount,
-		&article.CoverImage, &article.CreatedAt, &article.UpdatedAt,
-		&author.ID, &author.Username, &author.Email, &author.Avatar,
-		&author.Role, &author.CreatedAt, &author.UpdatedAt,
-	)
+	// 按发布时间（未发布时按创建时间）的年月分组，年份由月份汇总
+	months, err := r.facetQuery(`
+		SELECT m.month, COUNT(*)
+		FROM (SELECT `+r.monthOf("COALESCE(a.published_at, a.created_at)")+` AS month FROM articles a`+where+`) m
+		GROUP BY m.month`, q.args)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("article not found")
+		return nil, err
+	}
+	years := make(map[string]int)
+	for month, count := range months {
+		if len(month) >= 4 {
+			years[month[:4]] += count
 		}
+	}
+
+	tags, err := r.facetQuery(`
+		SELECT t.name, COUNT(*)
+		FROM article_tags at
+		JOIN tags t ON t.id = at.tag_id
+		WHERE at.article_id IN (SELECT a.id FROM articles a`+where+`)
+		GROUP BY t.name`, q.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SearchFacets{
+		Tags:    facetCounts(tags, false),
+		Authors: facetCounts(authors, false),
+		Years:   facetCounts(years, true),
+		Months:  facetCounts(months, true),
+	}, nil
+}
+
+// facetQuery 执行返回 (值, 数量) 两列的分组统计
+func (r *ArticleRepository) facetQuery(query string, args []interface{}) (map[string]int, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		counts[value] = count
+	}
+	return counts, rows.Err()
+}
 
-	// Parse tags
+// monthOf 返回把时间列格式化为 YYYY-MM 的 SQL 表达式。SQLite 中时间以文本保存，取前 7 个字符
+func (r *ArticleRepository) monthOf(column string) string {
 	if r.dbType == "postgres" {
-		if err := pq.Array(&article.Tags).Scan(tagsStr); err != nil {
-			article.Tags = []string{}
+		return "to_char(" + column + ", 'YYYY-MM')"
+	}
+	return "substr(" + column + ", 1, 7)"
+}
+
+// facetCounts 按数量降序排列；byValue 为 true 时（年份、月份）按值降序排列
+func facetCounts(counts map[string]int, byValue bool) []model.FacetCount {
+	facets := make([]model.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, model.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if byValue {
+			return facets[i].Value > facets[j].Value
 		}
-	} else {
-		// For SQLite, parse comma-separated string
-		if tagsStr != "" {
-			article.Tags = strings.Split(tagsStr, ",")
-		} else {
-			article.Tags = []string{}
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
 		}
-	}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
 
-	article.Author = author
+func (r *ArticleRepository) GetByID(id int) (*model.Article, error) {
+	return r.getViewed("a.id", id)
+}
+
+func (r *ArticleRepository) GetByTitle(title string) (*model.Article, error) {
+	return r.getViewed("a.title", title)
+}
+
+func (r *ArticleRepository) GetBySlug(slug string) (*model.Article, error) {
+	return r.getViewed("a.slug", slug)
+}
+
+// GetByIDForEdit 获取文章用于编辑、比较版本等后台操作，不增加浏览量
+func (r *ArticleRepository) GetByIDForEdit(id int) (*model.Article, error) {
+	return r.getBy("a.id", id)
+}
+
+// getViewed 获取文章并增加浏览量，未发布和私密的文章不计浏览量
+func (r *ArticleRepository) getViewed(column string, value interface{}) (*model.Article, error) {
+	article, err := r.getBy(column, value)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != "published" || article.Visibility == model.VisibilityPrivate {
+		return article, nil
+	}
 
-	_, err = r.db.Exec("UPDATE articles SET view_count = view_count + 1 WHERE id = ?", id)
+	_, err = r.db.Exec("UPDATE articles SET view_count = view_count + 1 WHERE id = ?", article.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -332,27 +574,30 @@ func (r *ArticleRepository) GetByID(id int) (*model.Article, error) {
 	return article, nil
 }
 
-func (r *ArticleRepository) GetByTitle(title string) (*model.Article, error) {
+// getBy 按唯一列查询单篇文章，column 只能是代码中的常量
+func (r *ArticleRepository) getBy(column string, value interface{}) (*model.Article, error) {
 	article := &model.Article{}
 	author := &model.User{}
-	var tagsStr string
 
 	query := `
-		SELECT a.id, a.title, a.content, a.summary, a.tags, a.author_id, a.status,
-			   a.view_count, a.like_count, a.comment_count, a.cover_image,
-			   a.created_at, a.updated_at,
+		SELECT a.id, a.title, a.slug, a.content, a.summary, a.summary_auto, a.author_id, a.status, a.version, a.category_id, a.visibility, a.password_hash, a.expires_at, a.expiry_action,
+			   a.pinned, a.pinned_until, a.featured,
+			   a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time, a.cover_image,
+			   a.created_at, a.updated_at, a.published_at, a.content_html, a.toc,
 			   u.id, u.username, u.email, u.avatar, u.role, u.created_at, u.updated_at
 		FROM articles a
 		JOIN users u ON a.author_id = u.id
-		WHERE a.title = ?
+		WHERE ` + column + ` = ? AND a.deleted_at IS NULL
 	`
 
-	row := r.db.QueryRow(query, title)
+	var tocJSON string
+	row := r.db.QueryRow(query, value)
 	err := row.Scan(
-		&article.ID, &article.Title, &article.Content, &article.Summary,
-		&tagsStr, &article.AuthorID, &article.Status,
-		&article.ViewCount, &article.LikeCount, &article.CommentCount,
-		&article.CoverImage, &article.CreatedAt, &article.UpdatedAt,
+		&article.ID, &article.Title, &article.Slug, &article.Content, &article.Summary, &article.SummaryAuto,
+		&article.AuthorID, &article.Status, &article.Version, &article.CategoryID, &article.Visibility, &article.PasswordHash, &article.ExpiresAt, &article.ExpiryAction,
+		&article.Pinned, &article.PinnedUntil, &article.Featured,
+		&article.ViewCount, &article.LikeCount, &article.CommentCount, &article.WordCount, &article.ReadingTime,
+		&article.CoverImage, &article.CreatedAt, &article.UpdatedAt, &article.PublishedAt, &article.ContentHTML, &tocJSON,
 		&author.ID, &author.Username, &author.Email, &author.Avatar,
 		&author.Role, &author.CreatedAt, &author.UpdatedAt,
 	)
@@ -363,126 +608,277 @@ func (r *ArticleRepository) GetByTitle(title string) (*model.Article, error) {
 		return nil, err
 	}
 
-	// Parse tags
-	if r.dbType == "postgres" {
-		if err := pq.Array(&article.Tags).Scan(tagsStr); err != nil {
-			article.Tags = []string{}
-		}
-	} else {
-		// For SQLite, parse comma-separated string
-		if tagsStr != "" {
-			article.Tags = strings.Split(tagsStr, ",")
-		} else {
-			article.Tags = []string{}
-		}
-	}
-
 	article.Author = author
+	if article.TOC, err = parseTOC(tocJSON); err != nil {
+		return nil, err
+	}
 
-	_, err = r.db.Exec("UPDATE articles SET view_count = view_count + 1 WHERE id = ?", article.ID)
-	if err != nil {
+	if article.Tags, err = getArticleTags(r.db, article.ID); err != nil {
 		return nil, err
 	}
-	article.ViewCount++
+	if article.CategoryID != nil {
+		if article.Category, err = getArticleCategory(r.db, *article.CategoryID); err != nil {
+			return nil, err
+		}
+	}
 
 	return article, nil
 }
 
 func (r *ArticleRepository) Create(article *model.Article) error {
-	var tagsValue interface{}
-	if r.dbType == "postgres" {
-		tagsValue = pq.Array(article.Tags)
-	} else {
-		tagsValue = strings.Join(article.Tags, ",")
-	}
+	return r.inTx(func(tx *sql.Tx) error { return r.create(tx, article) })
+}
 
-	query := `
-		INSERT INTO articles (title, content, summary, tags, author_id, status, cover_image, published_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`
+func (r *ArticleRepository) Update(article *model.Article) error {
+	return r.inTx(func(tx *sql.Tx) error { return r.update(tx, article) })
+}
 
-	result, err := r.db.Exec(query,
-		article.Title, article.Content, article.Summary,
-		tagsValue, article.AuthorID, article.Status, article.CoverImage, article.PublishedAt,
-	)
+// inTx 在事务中执行写操作，保证文章与标签关联一起写入
+func (r *ArticleRepository) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	id, err := result.LastInsertId()
-	if err != nil {
+	if err := fn(tx); err != nil {
 		return err
 	}
-	article.ID = int(id)
-
-	return nil
+	return tx.Commit()
 }
 
-func (r *ArticleRepository) Update(article *model.Article) error {
-	var tagsValue interface{}
-	if r.dbType == "postgres" {
-		tagsValue = pq.Array(article.Tags)
-	} else {
-		tagsValue = strings.Join(article.Tags, ",")
-	}
-
-	query := `
-		UPDATE articles 
-		SET title = ?, content = ?, summary = ?, tags = ?, status = ?, 
-		    cover_image = ?, published_at = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
-
-	_, err := r.db.Exec(query,
-		article.Title, article.Content, article.Summary,
-		tagsValue, article.Status, article.CoverImage, article.PublishedAt, article.ID,
-	)
-
-	return err
+// Begin 开启事务，配合 *Tx 系列方法实现批量写入
+func (r *ArticleRepository) Begin() (*sql.Tx, error) {
+	return r.db.Begin()
 }
 
-func (r *ArticleRepository) Delete(id int) error {
-	_, err := r.db.Exec("DELETE FROM articles WHERE id = ?", id)
-	return err
+func (r *ArticleRepository) CreateTx(tx *sql.Tx, article *model.Article) error {
+	return r.create(tx, article)
 }
 
-func (r *ArticleRepository) Like(userID, articleID int) error {
-	tx, err := r.db.Begin()
+// OverwriteTx 用新的内容覆盖已有文章，保留 ID、浏览量等统计数据
+func (r *ArticleRepository) OverwriteTx(tx *sql.Tx, article *model.Article) error {
+	// 导入的内容以文件为准，不做版本检查；分类、可见性、密码和到期设置保留站内原有的值，
+	// 不使用导入文件中的值（文件中没有密码哈希，分类 ID 也可能来自其他站点）
+	err := tx.QueryRow(
+		"SELECT version, category_id, visibility, password_hash, expires_at, expiry_action FROM articles WHERE id = ?", article.ID,
+	).Scan(&article.Version, &article.CategoryID, &article.Visibility, &article.PasswordHash, &article.ExpiresAt, &article.ExpiryAction)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	if err := r.update(tx, article); err != nil {
+		return err
+	}
+	// 覆盖回收站中的同名文章时一并恢复
+	_, err = tx.Exec("UPDATE articles SET author_id = ?, deleted_at = NULL WHERE id = ?", article.AuthorID, article.ID)
+	return err
+}
 
-	if r.dbType == "postgres" {
-		_, err = tx.Exec("INSERT INTO likes (user_id, article_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, articleID)
-	} else {
-		_, err = tx.Exec("INSERT OR IGNORE INTO likes (user_id, article_id) VALUES (?, ?)", userID, articleID)
+// GetIDByTitleTx 查找同名文章，不存在时返回 0
+func (r *ArticleRepository) GetIDByTitleTx(tx *sql.Tx, title string) (int, error) {
+	var id int
+	err := tx.QueryRow("SELECT id FROM articles WHERE title = ?", title).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
 	}
+	return id, err
+}
+
+func (r *ArticleRepository) create(q execer, article *model.Article) error {
+	// 导入时保留原始创建时间，新建文章由数据库填充
+	var createdAt, updatedAt interface{}
+	if !article.CreatedAt.IsZero() {
+		createdAt = article.CreatedAt.UTC()
+	}
+	if !article.UpdatedAt.IsZero() {
+		updatedAt = article.UpdatedAt.UTC()
+	}
+
+	slug, err := r.uniqueSlug(q, slugBase(article), 0)
 	if err != nil {
 		return err
 	}
+	article.Slug = slug
 
-	_, err = tx.Exec("UPDATE articles SET like_count = (SELECT COUNT(*) FROM likes WHERE article_id = ?) WHERE id = ?", articleID, articleID)
+	tocJSON, err := renderContent(article)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit()
-}
+	query := `
+		INSERT INTO articles (title, slug, content, content_html, toc, word_count, reading_time, summary, summary_auto, author_id, status, category_id,
+		                      visibility, password_hash, expires_at, expiry_action, cover_image, published_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP))
+	`
 
-func (r *ArticleRepository) Unlike(userID, articleID int) error {
-	tx, err := r.db.Begin()
+	if article.Visibility == "" {
+		article.Visibility = model.VisibilityPublic
+	}
+	if article.ExpiryAction == "" {
+		article.ExpiryAction = model.ExpiryUnpublish
+	}
+	result, err := q.Exec(query,
+		article.Title, article.Slug, article.Content, article.ContentHTML, tocJSON, article.WordCount, article.ReadingTime, article.Summary, article.SummaryAuto,
+		article.AuthorID, article.Status, article.CategoryID, article.Visibility, article.PasswordHash,
+		article.ExpiresAt, article.ExpiryAction, article.CoverImage, article.PublishedAt,
+		createdAt, updatedAt,
+	)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
 
-	_, err = tx.Exec("DELETE FROM likes WHERE user_id = ? AND article_id = ?", userID, articleID)
+	id, err := result.LastInsertId()
 	if err != nil {
 		return err
 	}
+	article.ID = int(id)
+	article.Version = 1
 
-	_, err = tx.Exec("UPDATE articles SET like_count = (SELECT COUNT(*) FROM likes WHERE article_id = ?) WHERE id = ?", articleID, articleID)
+	return setArticleTags(q, article.ID, article.Tags)
+}
+
+func (r *ArticleRepository) update(q execer, article *model.Article) error {
+	if err := r.changeSlug(q, article); err != nil {
+		return err
+	}
+	tocJSON, err := renderContent(article)
+	if err != nil {
+		return err
+	}
+
+	// 只有版本号与读取时一致才更新，防止覆盖其他人在此期间保存的修改
+	query := `
+		UPDATE articles 
+		SET title = ?, slug = ?, content = ?, content_html = ?, toc = ?, word_count = ?, reading_time = ?, summary = ?, summary_auto = ?, status = ?, category_id = ?,
+		    visibility = ?, password_hash = ?, expires_at = ?, expiry_action = ?,
+		    cover_image = ?, published_at = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ? AND version = ?
+	`
+
+	result, err := q.Exec(query,
+		article.Title, article.Slug, article.Content, article.ContentHTML, tocJSON, article.WordCount, article.ReadingTime, article.Summary, article.SummaryAuto,
+		article.Status, article.CategoryID, article.Visibility, article.PasswordHash,
+		article.ExpiresAt, article.ExpiryAction, article.CoverImage, article.PublishedAt, article.ID, article.Version,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrVersionConflict
+	}
+	article.Version++
+
+	return setArticleTags(q, article.ID, article.Tags)
+}
+
+// Delete 把文章移入回收站，评论、点赞等关联数据保留，直到被 Purge 永久删除
+func (r *ArticleRepository) Delete(id int) error {
+	result, err := r.db.Exec("UPDATE articles SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("article not found")
+	}
+	return nil
+}
+
+// Restore 把文章移出回收站
+func (r *ArticleRepository) Restore(id int) error {
+	result, err := r.db.Exec("UPDATE articles SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("article not found in trash")
+	}
+	return nil
+}
+
+// Purge 永久删除回收站中的文章及其评论、点赞、标签关联、历史 slug 与历史版本
+func (r *ArticleRepository) Purge(id int) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		var deleted int
+		err := tx.QueryRow("SELECT COUNT(*) FROM articles WHERE id = ? AND deleted_at IS NOT NULL", id).Scan(&deleted)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return fmt.Errorf("article not found in trash")
+		}
+
+		// SQLite 未开启外键约束，不能依赖 ON DELETE CASCADE
+		for _, table := range []string{"comments", "likes", "article_tags", "article_slugs", "article_revisions", "article_autosaves", "series_articles"} {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE article_id = ?", id); err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec("DELETE FROM articles WHERE id = ?", id)
+		return err
+	})
+}
+
+// GetTrashedBefore 返回在 cutoff 之前移入回收站的文章 ID
+func (r *ArticleRepository) GetTrashedBefore(cutoff time.Time) ([]int, error) {
+	rows, err := r.db.Query("SELECT id FROM articles WHERE deleted_at IS NOT NULL AND deleted_at < ?", r.timeArg(cutoff))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Like 点赞文章，回收站中的文章返回 article not found
+func (r *ArticleRepository) Like(userID, articleID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireActiveArticle(tx, articleID); err != nil {
+		return err
+	}
+
+	if r.dbType == "postgres" {
+		_, err = tx.Exec("INSERT INTO likes (user_id, article_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, articleID)
+	} else {
+		_, err = tx.Exec("INSERT OR IGNORE INTO likes (user_id, article_id) VALUES (?, ?)", userID, articleID)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE articles SET like_count = (SELECT COUNT(*) FROM likes WHERE article_id = ?) WHERE id = ?", articleID, articleID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ArticleRepository) Unlike(userID, articleID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM likes WHERE user_id = ? AND article_id = ?", userID, articleID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE articles SET like_count = (SELECT COUNT(*) FROM likes WHERE article_id = ?) WHERE id = ?", articleID, articleID)
 	if err != nil {
 		return err
 	}
@@ -491,16 +887,83 @@ func (r *ArticleRepository) Unlike(userID, articleID int) error {
 }
 
 func (r *ArticleRepository) Unpublish(id int) error {
-	_, err := r.db.Exec("UPDATE articles SET status = 'draft', published_at = NULL WHERE id = ?", id)
+	_, err := r.db.Exec("UPDATE articles SET status = 'draft', published_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NULL", id)
+	return err
+}
+
+// GetExpiredArticles 返回到期时间已过的已发布文章
+func (r *ArticleRepository) GetExpiredArticles(now time.Time) ([]model.Article, error) {
+	rows, err := r.db.Query(`
+		SELECT id, title, expires_at, expiry_action FROM articles
+		WHERE status = 'published' AND expires_at IS NOT NULL AND expires_at <= ? AND deleted_at IS NULL
+	`, r.timeArg(now))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []model.Article
+	for rows.Next() {
+		var article model.Article
+		if err := rows.Scan(&article.ID, &article.Title, &article.ExpiresAt, &article.ExpiryAction); err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+	return articles, rows.Err()
+}
+
+// Expire 按到期处理方式下线文章并清除到期时间：unpublish 转为草稿，archive 转为不公开列出
+func (r *ArticleRepository) Expire(id int, action string) error {
+	query := "UPDATE articles SET status = 'draft', expires_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND status = 'published'"
+	if action == model.ExpiryArchive {
+		query = "UPDATE articles SET visibility = 'unlisted', expires_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND status = 'published'"
+	}
+	_, err := r.db.Exec(query, id)
 	return err
 }
 
+// SetPinned 置顶或取消置顶文章，不修改版本号和更新时间
+func (r *ArticleRepository) SetPinned(id int, pinned bool, until *time.Time) error {
+	return r.setFlags(id, "pinned = ?, pinned_until = ?", pinned, until)
+}
+
+// SetFeatured 设置或取消精选，不修改版本号和更新时间
+func (r *ArticleRepository) SetFeatured(id int, featured bool) error {
+	return r.setFlags(id, "featured = ?", featured)
+}
+
+// setFlags 更新文章的展示标记，assignments 只能是代码中的常量
+func (r *ArticleRepository) setFlags(id int, assignments string, values ...interface{}) error {
+	result, err := r.db.Exec("UPDATE articles SET "+assignments+" WHERE id = ? AND deleted_at IS NULL", append(values, id)...)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("article not found")
+	}
+	return nil
+}
+
+// UnpinExpired 取消置顶时间已过的文章的置顶，返回处理的文章数
+func (r *ArticleRepository) UnpinExpired(now time.Time) (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE articles SET pinned = FALSE, pinned_until = NULL WHERE pinned = TRUE AND pinned_until IS NOT NULL AND pinned_until <= ?",
+		r.timeArg(now),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (r *ArticleRepository) GetScheduledArticles() ([]model.Article, error) {
 	var articles []model.Article
 
 	query := `
-		SELECT a.id, a.title, a.content, a.summary, a.tags, a.author_id, a.status,
-			   a.view_count, a.like_count, a.comment_count, a.cover_image,
+		SELECT a.id, a.title, a.slug, a.content, a.summary, a.summary_auto, a.author_id, a.status, a.version, a.category_id, a.visibility, a.password_hash, a.expires_at, a.expiry_action,
+			   a.pinned, a.pinned_until, a.featured,
+			   a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time, a.cover_image,
 			   a.created_at, a.updated_at, a.published_at, a.deleted_at,
 			   u.id, u.username, u.email, u.avatar, u.role, u.created_at, u.updated_at
 		FROM articles a
@@ -517,12 +980,12 @@ func (r *ArticleRepository) GetScheduledArticles() ([]model.Article, error) {
 	for rows.Next() {
 		var article model.Article
 		var author model.User
-		var tagsStr string
 
 		err := rows.Scan(
-			&article.ID, &article.Title, &article.Content, &article.Summary,
-			&tagsStr, &article.AuthorID, &article.Status,
-			&article.ViewCount, &article.LikeCount, &article.CommentCount,
+			&article.ID, &article.Title, &article.Slug, &article.Content, &article.Summary, &article.SummaryAuto,
+			&article.AuthorID, &article.Status, &article.Version, &article.CategoryID, &article.Visibility, &article.PasswordHash, &article.ExpiresAt, &article.ExpiryAction,
+			&article.Pinned, &article.PinnedUntil, &article.Featured,
+			&article.ViewCount, &article.LikeCount, &article.CommentCount, &article.WordCount, &article.ReadingTime,
 			&article.CoverImage, &article.CreatedAt, &article.UpdatedAt, &article.PublishedAt, &article.DeletedAt,
 			&author.ID, &author.Username, &author.Email, &author.Avatar,
 			&author.Role, &author.CreatedAt, &author.UpdatedAt,
@@ -531,23 +994,66 @@ func (r *ArticleRepository) GetScheduledArticles() ([]model.Article, error) {
 			return nil, err
 		}
 
-		// Parse tags
-		if r.dbType == "postgres" {
-			if err := pq.Array(&article.Tags).Scan(tagsStr); err != nil {
-				article.Tags = []string{}
-			}
-		} else {
-			// For SQLite, parse comma-separated string
-			if tagsStr != "" {
-				article.Tags = strings.Split(tagsStr, ",")
-			} else {
-				article.Tags = []string{}
-			}
+		article.Author = &author
+		articles = append(articles, article)
+	}
+
+	if err := r.loadTags(articles); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetAllForExport 返回全部文章（含草稿，不含回收站中的文章），按 ID 升序，不分页
+func (r *ArticleRepository) GetAllForExport() ([]model.Article, error) {
+	articles := []model.Article{}
+
+	query := `
+		SELECT a.id, a.title, a.slug, a.content, a.summary, a.summary_auto, a.author_id, a.status, a.version, a.category_id, a.visibility, a.password_hash, a.expires_at, a.expiry_action,
+			   a.pinned, a.pinned_until, a.featured,
+			   a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time, a.cover_image,
+			   a.created_at, a.updated_at, a.published_at, a.deleted_at,
+			   u.id, u.username, u.email, u.avatar, u.role, u.created_at, u.updated_at
+		FROM articles a
+		JOIN users u ON a.author_id = u.id
+		WHERE a.deleted_at IS NULL
+		ORDER BY a.id ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article model.Article
+		var author model.User
+
+		err := rows.Scan(
+			&article.ID, &article.Title, &article.Slug, &article.Content, &article.Summary, &article.SummaryAuto,
+			&article.AuthorID, &article.Status, &article.Version, &article.CategoryID, &article.Visibility, &article.PasswordHash, &article.ExpiresAt, &article.ExpiryAction,
+			&article.Pinned, &article.PinnedUntil, &article.Featured,
+			&article.ViewCount, &article.LikeCount, &article.CommentCount, &article.WordCount, &article.ReadingTime,
+			&article.CoverImage, &article.CreatedAt, &article.UpdatedAt, &article.PublishedAt, &article.DeletedAt,
+			&author.ID, &author.Username, &author.Email, &author.Avatar,
+			&author.Role, &author.CreatedAt, &author.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
 		}
 
 		article.Author = &author
 		articles = append(articles, article)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadTags(articles); err != nil {
+		return nil, err
+	}
 
 	return articles, nil
 }
@@ -714,6 +1220,7 @@ func (r *CommentRepository) GetRepliesByCommentID(commentID int, page int, pageS
 	return rootReplies[start:end], totalCount, nil
 }
 
+// Create 写入评论并增加文章评论数，回收站中的文章返回 article not found
 func (r *CommentRepository) Create(comment *model.Comment) error {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -721,6 +1228,10 @@ func (r *CommentRepository) Create(comment *model.Comment) error {
 	}
 	defer tx.Rollback()
 
+	if err := requireActiveArticle(tx, comment.ArticleID); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO comments (content, author_id, article_id, parent_id)
 		VALUES (?, ?, ?, ?)
@@ -745,6 +1256,36 @@ func (r *CommentRepository) Create(comment *model.Comment) error {
 	return tx.Commit()
 }
 
+// CreateTx 在导入事务中写入评论并保留原始时间，文章评论数由 RefreshCountTx 统一更新
+func (r *CommentRepository) CreateTx(tx *sql.Tx, comment *model.Comment) error {
+	var createdAt interface{}
+	if !comment.CreatedAt.IsZero() {
+		createdAt = comment.CreatedAt.UTC()
+	}
+
+	query := `
+		INSERT INTO comments (content, author_id, article_id, parent_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP))
+	`
+	result, err := tx.Exec(query, comment.Content, comment.AuthorID, comment.ArticleID, comment.ParentID, createdAt, createdAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	comment.ID = int(id)
+
+	return nil
+}
+
+func (r *CommentRepository) RefreshCountTx(tx *sql.Tx, articleID int) error {
+	_, err := tx.Exec("UPDATE articles SET comment_count = (SELECT COUNT(*) FROM comments WHERE article_id = ? AND deleted_at IS NULL) WHERE id = ?", articleID, articleID)
+	return err
+}
+
 func (r *CommentRepository) Delete(id int) error {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -771,16 +1312,425 @@ func (r *CommentRepository) Delete(id int) error {
 	return tx.Commit()
 }
 
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at)
+		VALUES (?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	token.ID = int(id)
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(hash string) (*model.RefreshToken, error) {
+	token := &model.RefreshToken{}
+	query := `
+		SELECT id, user_id, token_hash, family_id, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = ?
+	`
+	err := r.db.QueryRow(query, hash).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.FamilyID,
+		&token.ExpiresAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// Rotate revokes the current token and stores its successor in the same family.
+// If the current token was already revoked (e.g. a concurrent refresh won the race)
+// nothing is written and ErrRefreshTokenReused is returned.
+func (r *RefreshTokenRepository) Rotate(current *model.RefreshToken, next *model.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL", current.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRefreshTokenReused
+	}
+
+	query := `
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at)
+		VALUES (?, ?, ?, ?)
+	`
+	result, err = tx.Exec(query, next.UserID, next.TokenHash, next.FamilyID, next.ExpiresAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	next.ID = int(id)
+
+	return tx.Commit()
+}
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(session *model.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip_address, last_seen_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query,
+		session.ID, session.UserID, session.UserAgent, session.IPAddress,
+		session.LastSeenAt.UTC(), session.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+func (r *SessionRepository) GetByID(id string) (*model.Session, error) {
+	session := &model.Session{}
+	query := `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at
+		FROM sessions WHERE id = ?
+	`
+	err := r.db.QueryRow(query, id).Scan(
+		&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+		&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("session not found")
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+func (r *SessionRepository) GetActiveByUserID(userID int) ([]model.Session, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at
+		FROM sessions
+		WHERE user_id = ? AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []model.Session{}
+	for rows.Next() {
+		var session model.Session
+		err := rows.Scan(
+			&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *SessionRepository) Touch(id string, lastSeenAt time.Time) error {
+	_, err := r.db.Exec("UPDATE sessions SET last_seen_at = ? WHERE id = ?", lastSeenAt.UTC(), id)
+	return err
+}
+
+// Extend 在 refresh token 轮换时顺延会话有效期
+func (r *SessionRepository) Extend(id string, ipAddress string, expiresAt time.Time) error {
+	_, err := r.db.Exec("UPDATE sessions SET ip_address = ?, last_seen_at = ?, expires_at = ? WHERE id = ?",
+		ipAddress, time.Now().UTC(), expiresAt.UTC(), id)
+	return err
+}
+
+// Revoke 吊销会话及其 refresh token 家族（会话 ID 即家族 ID）
+func (r *SessionRepository) Revoke(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SessionRepository) RevokeAllByUserID(userID int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL", userID)
+	if err != nil {
+		return 0, err
+	}
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL", userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(revoked), tx.Commit()
+}
+
+type MediaRepository struct {
+	db *sql.DB
+}
+
+func NewMediaRepository(db *sql.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+const mediaColumns = `
+	m.id, m.file_name, m.webp_file_name, m.original_name, m.mime_type, m.size,
+	m.width, m.height, m.sha256, m.uploader_id, COALESCE(u.username, ''), m.alt_text,
+	m.files, m.created_at, m.updated_at
+`
+
+func scanMedia(scanner interface{ Scan(...interface{}) error }) (*model.Media, error) {
+	media := &model.Media{}
+	var files string
+	err := scanner.Scan(
+		&media.ID, &media.FileName, &media.WebPFileName, &media.OriginalName, &media.MimeType, &media.Size,
+		&media.Width, &media.Height, &media.SHA256, &media.UploaderID, &media.UploaderName, &media.AltText,
+		&files, &media.CreatedAt, &media.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if files != "" {
+		if err := json.Unmarshal([]byte(files), &media.Files); err != nil {
+			return nil, fmt.Errorf("invalid media files for %s: %w", media.FileName, err)
+		}
+	}
+	return media, nil
+}
+
+func (r *MediaRepository) Create(media *model.Media) error {
+	files, err := json.Marshal(media.Files)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO media (file_name, webp_file_name, original_name, mime_type, size, width, height,
+			sha256, uploader_id, alt_text, files)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query,
+		media.FileName, media.WebPFileName, media.OriginalName, media.MimeType, media.Size,
+		media.Width, media.Height, media.SHA256, media.UploaderID, media.AltText, string(files),
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	media.ID = int(id)
+	media.CreatedAt = time.Now()
+	media.UpdatedAt = media.CreatedAt
+
+	return nil
+}
+
+func (r *MediaRepository) GetByID(id int) (*model.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media m LEFT JOIN users u ON m.uploader_id = u.id WHERE m.id = ?`
+	media, err := scanMedia(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("media not found")
+		}
+		return nil, err
+	}
+	return media, nil
+}
+
+// GetBySHA256 按内容哈希查找媒体，不存在时返回 nil
+func (r *MediaRepository) GetBySHA256(sha256 string) (*model.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media m LEFT JOIN users u ON m.uploader_id = u.id WHERE m.sha256 = ?`
+	media, err := scanMedia(r.db.QueryRow(query, sha256))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return media, err
+}
+
+func (r *MediaRepository) GetAll(params model.MediaSearchParams) ([]model.Media, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if params.Keyword != "" {
+		keyword := "%" + strings.ToLower(params.Keyword) + "%"
+		conditions = append(conditions, "(LOWER(m.original_name) LIKE ? OR LOWER(m.alt_text) LIKE ? OR LOWER(m.file_name) LIKE ?)")
+		args = append(args, keyword, keyword, keyword)
+	}
+	if params.MimeType != "" {
+		conditions = append(conditions, "m.mime_type = ?")
+		args = append(args, params.MimeType)
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM media m"+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + mediaColumns + ` FROM media m LEFT JOIN users u ON m.uploader_id = u.id` +
+		whereClause + ` ORDER BY m.created_at DESC, m.id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.Query(query, append(args, params.PageSize, (params.Page-1)*params.PageSize)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	media := []model.Media{}
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		media = append(media, *item)
+	}
+	return media, total, rows.Err()
+}
+
+// ListAll 返回全部媒体，供孤立文件清理使用
+func (r *MediaRepository) ListAll() ([]model.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media m LEFT JOIN users u ON m.uploader_id = u.id ORDER BY m.id`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []model.Media
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, *item)
+	}
+	return media, rows.Err()
+}
+
+func (r *MediaRepository) UpdateAltText(id int, altText string) error {
+	result, err := r.db.Exec("UPDATE media SET alt_text = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", altText, id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("media not found")
+	}
+	return nil
+}
+
+func (r *MediaRepository) Delete(id int) error {
+	_, err := r.db.Exec("DELETE FROM media WHERE id = ?", id)
+	return err
+}
+
+// mediaReferenceTables 为正文与封面可能引用媒体文件的表，恢复历史版本或发布自动保存时会重新用到其中的文件
+var mediaReferenceTables = []string{"articles", "article_revisions", "article_autosaves"}
+
+// IsReferenced 检查文章（包括回收站中的文章）、历史版本和自动保存的正文或封面，以及用户头像是否引用了该文件。
+// 缩略图（name-320w.jpg）和 WebP 版本（name.webp）与原图共用文件名前缀，因此按不含扩展名的文件名匹配
+func (r *MediaRepository) IsReferenced(fileName string) (bool, error) {
+	pattern := "%" + strings.TrimSuffix(fileName, path.Ext(fileName)) + "%"
+	for _, table := range mediaReferenceTables {
+		var count int
+		err := r.db.QueryRow(
+			"SELECT COUNT(*) FROM "+table+" WHERE content LIKE ? OR cover_image LIKE ?",
+			pattern, pattern,
+		).Scan(&count)
+		if err != nil || count > 0 {
+			return count > 0, err
+		}
+	}
+
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM users WHERE avatar LIKE ?", pattern).Scan(&count)
+	return count > 0, err
+}
+
 type Repository struct {
-	User    *UserRepository
-	Article *ArticleRepository
-	Comment *CommentRepository
+	User         *UserRepository
+	Article      *ArticleRepository
+	Comment      *CommentRepository
+	RefreshToken *RefreshTokenRepository
+	Session      *SessionRepository
+	Media        *MediaRepository
+	Tag          *TagRepository
+	Series       *SeriesRepository
+	Category     *CategoryRepository
 }
 
 func New(db *sql.DB) *Repository {
 	return &Repository{
-		User:    NewUserRepository(db),
-		Article: NewArticleRepository(db),
-		Comment: NewCommentRepository(db),
+		User:         NewUserRepository(db),
+		Article:      NewArticleRepository(db),
+		Comment:      NewCommentRepository(db),
+		RefreshToken: NewRefreshTokenRepository(db),
+		Session:      NewSessionRepository(db),
+		Media:        NewMediaRepository(db),
+		Tag:          NewTagRepository(db),
+		Series:       NewSeriesRepository(db),
+		Category:     NewCategoryRepository(db),
 	}
 }
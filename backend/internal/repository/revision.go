@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"pea-blog-backend/internal/model"
+)
+
+// UpdateWithRevision 在同一事务中保存更新前的快照并更新文章，revision 为 nil 时只更新文章
+func (r *ArticleRepository) UpdateWithRevision(article *model.Article, revision *model.ArticleRevision) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		if revision != nil {
+			if err := createRevision(tx, revision); err != nil {
+				return err
+			}
+		}
+		return r.update(tx, article)
+	})
+}
+
+func createRevision(q execer, revision *model.ArticleRevision) error {
+	err := q.QueryRow(
+		"SELECT COALESCE(MAX(revision), 0) + 1 FROM article_revisions WHERE article_id = ?",
+		revision.ArticleID,
+	).Scan(&revision.Revision)
+	if err != nil {
+		return err
+	}
+
+	tags, err := json.Marshal(normalizeTags(revision.Tags))
+	if err != nil {
+		return err
+	}
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(`
+		INSERT INTO article_revisions (article_id, revision, title, slug, content, summary, tags, status, cover_image, changes, editor_id,
+		                               category_id, visibility, password_hash, expires_at, expiry_action)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		revision.ArticleID, revision.Revision, revision.Title, revision.Slug, revision.Content,
+		revision.Summary, string(tags), revision.Status, revision.CoverImage, string(changes), revision.EditorID,
+		revision.CategoryID, revision.Visibility, revision.PasswordHash, revision.ExpiresAt, revision.ExpiryAction,
+	)
+	return err
+}
+
+// GetRevisions 按版本号降序返回文章的全部版本，不含正文
+func (r *ArticleRepository) GetRevisions(articleID int) ([]model.ArticleRevision, error) {
+	rows, err := r.db.Query(`
+		SELECT rv.id, rv.article_id, rv.revision, rv.title, rv.slug, '', rv.summary, rv.tags,
+			   rv.status, rv.cover_image, rv.changes, rv.editor_id, COALESCE(u.username, ''), rv.created_at,
+			   rv.category_id, COALESCE(rv.visibility, 'public'), COALESCE(rv.password_hash, ''), rv.expires_at, COALESCE(rv.expiry_action, 'unpublish')
+		FROM article_revisions rv
+		LEFT JOIN users u ON u.id = rv.editor_id
+		WHERE rv.article_id = ?
+		ORDER BY rv.revision DESC
+	`, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []model.ArticleRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *ArticleRepository) GetRevision(articleID, revision int) (*model.ArticleRevision, error) {
+	row := r.db.QueryRow(`
+		SELECT rv.id, rv.article_id, rv.revision, rv.title, rv.slug, rv.content, rv.summary, rv.tags,
+			   rv.status, rv.cover_image, rv.changes, rv.editor_id, COALESCE(u.username, ''), rv.created_at,
+			   rv.category_id, COALESCE(rv.visibility, 'public'), COALESCE(rv.password_hash, ''), rv.expires_at, COALESCE(rv.expiry_action, 'unpublish')
+		FROM article_revisions rv
+		LEFT JOIN users u ON u.id = rv.editor_id
+		WHERE rv.article_id = ? AND rv.revision = ?
+	`, articleID, revision)
+
+	result, err := scanRevision(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("revision not found")
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func scanRevision(scanner interface{ Scan(...interface{}) error }) (*model.ArticleRevision, error) {
+	revision := &model.ArticleRevision{}
+	var tags, changes string
+	err := scanner.Scan(
+		&revision.ID, &revision.ArticleID, &revision.Revision, &revision.Title, &revision.Slug,
+		&revision.Content, &revision.Summary, &tags, &revision.Status, &revision.CoverImage,
+		&changes, &revision.EditorID, &revision.EditorName, &revision.CreatedAt,
+		&revision.CategoryID, &revision.Visibility, &revision.PasswordHash, &revision.ExpiresAt, &revision.ExpiryAction,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	revision.Tags = []string{}
+	revision.Changes = []string{}
+	if tags != "" {
+		if err := json.Unmarshal([]byte(tags), &revision.Tags); err != nil {
+			return nil, fmt.Errorf("invalid revision tags: %w", err)
+		}
+	}
+	if changes != "" {
+		if err := json.Unmarshal([]byte(changes), &revision.Changes); err != nil {
+			return nil, fmt.Errorf("invalid revision changes: %w", err)
+		}
+	}
+	return revision, nil
+}
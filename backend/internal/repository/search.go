@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"pea-blog-backend/internal/util"
+)
+
+// SQLite trigram 分词器无法匹配少于 3 个字符的词
+const minTrigramTermLength = 3
+
+// 各列的相关度权重：标题 > 摘要 > 正文
+const sqliteBM25Weights = "10.0, 4.0, 1.0"
+
+// searchQuery 把关键词拆成全文索引可用的词和需要退化为 LIKE 的词
+type searchQuery struct {
+	dbType    string
+	fullText  []string
+	likeTerms []string
+}
+
+func (r *ArticleRepository) buildSearch(keyword string) *searchQuery {
+	search := &searchQuery{dbType: r.dbType}
+	for _, term := range util.SplitSearchTerms(keyword) {
+		// Postgres 的 simple 分词按空格切分，中日韩文本无法按词匹配
+		if utf8.RuneCountInString(term) < minTrigramTermLength || (r.dbType == "postgres" && util.HasCJK(term)) {
+			search.likeTerms = append(search.likeTerms, term)
+		} else {
+			search.fullText = append(search.fullText, term)
+		}
+	}
+	return search
+}
+
+func (s *searchQuery) where(q *queryArgs) []string {
+	var conditions []string
+	if len(s.fullText) > 0 {
+		if s.dbType == "postgres" {
+			conditions = append(conditions, "a.search_vector @@ plainto_tsquery('simple', "+q.add(strings.Join(s.fullText, " "))+")")
+		} else {
+			conditions = append(conditions, "a.id IN (SELECT rowid FROM articles_fts WHERE articles_fts MATCH "+q.add(s.ftsMatch())+")")
+		}
+	}
+	for _, term := range s.likeTerms {
+		pattern := likePattern(term)
+		conditions = append(conditions, "("+s.like("a.title", q.add(pattern))+" OR "+
+			s.like("a.summary", q.add(pattern))+" OR "+s.like("a.content", q.add(pattern))+")")
+	}
+	return conditions
+}
+
+// rank 返回相关度表达式，值越大越相关
+func (s *searchQuery) rank(q *queryArgs) string {
+	var parts []string
+	if len(s.fullText) > 0 {
+		if s.dbType == "postgres" {
+			// setweight 已给标题 A、摘要 B、正文 C 权重
+			parts = append(parts, "ts_rank(a.search_vector, plainto_tsquery('simple', "+q.add(strings.Join(s.fullText, " "))+"))")
+		} else {
+			// bm25 越小越相关，取反
+			parts = append(parts, "COALESCE(-(SELECT bm25(articles_fts, "+sqliteBM25Weights+") FROM articles_fts WHERE articles_fts MATCH "+
+				q.add(s.ftsMatch())+" AND rowid = a.id), 0)")
+		}
+	}
+	for _, term := range s.likeTerms {
+		pattern := likePattern(term)
+		parts = append(parts, "(CASE WHEN "+s.like("a.title", q.add(pattern))+" THEN 10 WHEN "+
+			s.like("a.summary", q.add(pattern))+" THEN 4 ELSE 1 END)")
+	}
+	return "(" + strings.Join(parts, " + ") + ")"
+}
+
+// ftsMatch 生成 FTS5 查询，每个词加引号按短语匹配，词之间为 AND
+func (s *searchQuery) ftsMatch() string {
+	quoted := make([]string, len(s.fullText))
+	for i, term := range s.fullText {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+func (s *searchQuery) like(column, placeholder string) string {
+	if s.dbType == "postgres" {
+		return column + " ILIKE " + placeholder + ` ESCAPE '\'`
+	}
+	return column + " LIKE " + placeholder + ` ESCAPE '\'`
+}
+
+func likePattern(term string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(term) + "%"
+}
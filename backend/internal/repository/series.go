@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"pea-blog-backend/internal/model"
+)
+
+type SeriesRepository struct {
+	db *sql.DB
+}
+
+func NewSeriesRepository(db *sql.DB) *SeriesRepository {
+	return &SeriesRepository{db: db}
+}
+
+// GetAll 返回全部系列，按最近更新排序。publishedOnly 为 true 时只包含已发布的文章
+func (r *SeriesRepository) GetAll(publishedOnly bool) ([]model.Series, error) {
+	rows, err := r.db.Query(`
+		SELECT id, title, description, created_at, updated_at
+		FROM series
+		ORDER BY updated_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seriesList := []model.Series{}
+	for rows.Next() {
+		var series model.Series
+		if err := rows.Scan(&series.ID, &series.Title, &series.Description, &series.CreatedAt, &series.UpdatedAt); err != nil {
+			return nil, err
+		}
+		seriesList = append(seriesList, series)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	parts, err := r.getParts(0, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+	for i := range seriesList {
+		seriesList[i].Articles = parts[seriesList[i].ID]
+		if seriesList[i].Articles == nil {
+			seriesList[i].Articles = []model.SeriesArticle{}
+		}
+	}
+	return seriesList, nil
+}
+
+func (r *SeriesRepository) GetByID(id int, publishedOnly bool) (*model.Series, error) {
+	series := &model.Series{}
+	err := r.db.QueryRow(
+		"SELECT id, title, description, created_at, updated_at FROM series WHERE id = ?", id,
+	).Scan(&series.ID, &series.Title, &series.Description, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("series not found")
+		}
+		return nil, err
+	}
+
+	parts, err := r.getParts(id, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+	series.Articles = parts[id]
+	if series.Articles == nil {
+		series.Articles = []model.SeriesArticle{}
+	}
+	return series, nil
+}
+
+// GetByArticleID 返回文章所属的系列，文章不属于任何系列时返回 nil
+func (r *SeriesRepository) GetByArticleID(articleID int, publishedOnly bool) (*model.Series, error) {
+	var seriesID int
+	err := r.db.QueryRow("SELECT series_id FROM series_articles WHERE article_id = ?", articleID).Scan(&seriesID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(seriesID, publishedOnly)
+}
+
+// getParts 按系列分组返回文章，seriesID 为 0 时返回全部系列。回收站中的文章不计入，
+// publishedOnly 时也不包括不公开列出和私密的文章
+func (r *SeriesRepository) getParts(seriesID int, publishedOnly bool) (map[int][]model.SeriesArticle, error) {
+	query := `
+		SELECT sa.series_id, a.id, a.title, a.slug, a.status, a.published_at
+		FROM series_articles sa
+		JOIN articles a ON a.id = sa.article_id
+		WHERE a.deleted_at IS NULL`
+	var args []interface{}
+	if publishedOnly {
+		query += " AND a.status = 'published' AND " + listedVisibility
+	}
+	if seriesID != 0 {
+		query += " AND sa.series_id = ?"
+		args = append(args, seriesID)
+	}
+	query += " ORDER BY sa.series_id, sa.position"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parts := map[int][]model.SeriesArticle{}
+	for rows.Next() {
+		var id int
+		var part model.SeriesArticle
+		if err := rows.Scan(&id, &part.ID, &part.Title, &part.Slug, &part.Status, &part.PublishedAt); err != nil {
+			return nil, err
+		}
+		part.Position = len(parts[id]) + 1
+		parts[id] = append(parts[id], part)
+	}
+	return parts, rows.Err()
+}
+
+func (r *SeriesRepository) GetIDByTitle(title string) (int, error) {
+	var id int
+	err := r.db.QueryRow("SELECT id FROM series WHERE title = ?", title).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (r *SeriesRepository) Create(series *model.Series, articleIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO series (title, description) VALUES (?, ?)", series.Title, series.Description)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	series.ID = int(id)
+
+	if err := setSeriesArticles(tx, series.ID, articleIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update 修改系列信息并按 articleIDs 重新排列系列中的文章
+func (r *SeriesRepository) Update(series *model.Series, articleIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"UPDATE series SET title = ?, description = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		series.Title, series.Description, series.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("series not found")
+	}
+
+	if err := setSeriesArticles(tx, series.ID, articleIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setSeriesArticles 替换系列中的文章，已属于其他系列的文章从原系列移出
+func setSeriesArticles(tx *sql.Tx, seriesID int, articleIDs []int) error {
+	if _, err := tx.Exec("DELETE FROM series_articles WHERE series_id = ?", seriesID); err != nil {
+		return err
+	}
+	for i, articleID := range articleIDs {
+		var exists int
+		err := tx.QueryRow("SELECT COUNT(*) FROM articles WHERE id = ? AND deleted_at IS NULL", articleID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return fmt.Errorf("article not found")
+		}
+		if _, err := tx.Exec("DELETE FROM series_articles WHERE article_id = ?", articleID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(
+			"INSERT INTO series_articles (series_id, article_id, position) VALUES (?, ?, ?)",
+			seriesID, articleID, i+1,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete 删除系列，文章本身不受影响
+func (r *SeriesRepository) Delete(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// SQLite 未开启外键约束，不能依赖 ON DELETE CASCADE
+	if _, err := tx.Exec("DELETE FROM series_articles WHERE series_id = ?", id); err != nil {
+		return err
+	}
+	result, err := tx.Exec("DELETE FROM series WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("series not found")
+	}
+	return tx.Commit()
+}
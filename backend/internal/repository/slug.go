@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/util"
+)
+
+// slugBase 返回文章期望使用的 slug：指定了 Slug 时规范化它，否则由标题生成
+func slugBase(article *model.Article) string {
+	if article.Slug != "" {
+		return util.Slugify(article.Slug)
+	}
+	return util.Slugify(article.Title)
+}
+
+// uniqueSlug 在 base 已被其他文章（包括其历史 slug）占用时追加 -2、-3… 后缀
+func (r *ArticleRepository) uniqueSlug(q execer, base string, articleID int) (string, error) {
+	slug := base
+	for n := 2; ; n++ {
+		var count int
+		err := q.QueryRow(`
+			SELECT (SELECT COUNT(*) FROM articles WHERE slug = ? AND id != ?) +
+				   (SELECT COUNT(*) FROM article_slugs WHERE slug = ? AND article_id != ?)
+		`, slug, articleID, slug, articleID).Scan(&count)
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// changeSlug 确定更新后的 slug，并把旧 slug 记入历史以便重定向。
+// article.Slug 为空表示根据标题重新生成
+func (r *ArticleRepository) changeSlug(q execer, article *model.Article) error {
+	var current sql.NullString
+	err := q.QueryRow("SELECT slug FROM articles WHERE id = ?", article.ID).Scan(&current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("article not found")
+		}
+		return err
+	}
+
+	base := slugBase(article)
+	if current.Valid && current.String == base {
+		article.Slug = base
+		return nil
+	}
+
+	slug, err := r.uniqueSlug(q, base, article.ID)
+	if err != nil {
+		return err
+	}
+	article.Slug = slug
+	if current.String == "" || current.String == slug {
+		return nil
+	}
+
+	// 改回曾经用过的 slug 时，它不再需要重定向
+	if _, err := q.Exec("DELETE FROM article_slugs WHERE slug = ?", slug); err != nil {
+		return err
+	}
+	_, err = q.Exec(
+		"INSERT INTO article_slugs (slug, article_id) VALUES (?, ?) ON CONFLICT (slug) DO NOTHING",
+		current.String, article.ID,
+	)
+	return err
+}
+
+// GetCurrentSlug 查找历史 slug 对应文章的 ID 与当前 slug，不存在时返回 0 和空字符串
+func (r *ArticleRepository) GetCurrentSlug(oldSlug string) (int, string, error) {
+	var id int
+	var slug string
+	err := r.db.QueryRow(`
+		SELECT a.id, a.slug FROM article_slugs s
+		JOIN articles a ON a.id = s.article_id
+		WHERE s.slug = ? AND a.deleted_at IS NULL
+	`, oldSlug).Scan(&id, &slug)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	return id, slug, err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/pkg/database"
+)
+
+func newTestArticleRepository(t *testing.T) *ArticleRepository {
+	t.Helper()
+	db, err := database.Connect(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := Backfill(db); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	return NewArticleRepository(db)
+}
+
+func createTestArticle(t *testing.T, r *ArticleRepository, title string) *model.Article {
+	t.Helper()
+	// 迁移会创建 ID 为 1 的管理员
+	article := &model.Article{Title: title, Content: "content", AuthorID: 1, Status: "draft"}
+	if err := r.Create(article); err != nil {
+		t.Fatalf("create %q: %v", title, err)
+	}
+	return article
+}
+
+func TestUniqueSlug(t *testing.T) {
+	r := newTestArticleRepository(t)
+
+	hello := createTestArticle(t, r, "Hello")
+	second := createTestArticle(t, r, "Hello!")
+	if hello.Slug != "hello" || second.Slug != "hello-2" {
+		t.Fatalf("slugs = %q, %q, want hello, hello-2", hello.Slug, second.Slug)
+	}
+
+	// 改名后旧 slug 进入历史，仍然不能被其他文章使用
+	hello.Title = "World"
+	hello.Slug = ""
+	if err := r.Update(hello); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if hello.Slug != "world" {
+		t.Fatalf("renamed slug = %q, want world", hello.Slug)
+	}
+
+	tests := []struct {
+		name      string
+		base      string
+		articleID int
+		want      string
+	}{
+		{"free slug", "fresh", 0, "fresh"},
+		{"taken by current slug", "world", 0, "world-2"},
+		{"taken by history", "hello", 0, "hello-3"},
+		{"skips taken suffixes", "hello-2", 0, "hello-2-2"},
+		{"own current slug", "world", hello.ID, "world"},
+		{"own history slug", "hello", hello.ID, "hello"},
+		{"own suffixed slug", "hello-2", second.ID, "hello-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.uniqueSlug(r.db, tt.base, tt.articleID)
+			if err != nil {
+				t.Fatalf("uniqueSlug: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("uniqueSlug(%q, %d) = %q, want %q", tt.base, tt.articleID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackfillSlugs(t *testing.T) {
+	r := newTestArticleRepository(t)
+	first := createTestArticle(t, r, "Hello")
+	second := createTestArticle(t, r, "Hello!")
+	// 模拟添加 slug 列之前就存在的文章
+	if _, err := r.db.Exec("UPDATE articles SET slug = NULL"); err != nil {
+		t.Fatalf("clear slugs: %v", err)
+	}
+
+	if err := Backfill(r.db); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+
+	for id, want := range map[int]string{first.ID: "hello", second.ID: "hello-2"} {
+		var slug string
+		if err := r.db.QueryRow("SELECT slug FROM articles WHERE id = ?", id).Scan(&slug); err != nil {
+			t.Fatalf("load slug: %v", err)
+		}
+		if slug != want {
+			t.Errorf("article %d slug = %q, want %q", id, slug, want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"pea-blog-backend/internal/model"
+)
+
+// 每次 IN 查询最多携带的文章 ID 数
+const tagLoadBatchSize = 500
+
+// normalizeTags 去掉首尾空白、空标签和重复标签，保持原有顺序
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// setArticleTags 用给定标签替换文章的全部标签，不存在的标签会被创建
+func setArticleTags(q execer, articleID int, tags []string) error {
+	if _, err := q.Exec("DELETE FROM article_tags WHERE article_id = ?", articleID); err != nil {
+		return err
+	}
+
+	for position, name := range normalizeTags(tags) {
+		tagID, err := ensureTag(q, name)
+		if err != nil {
+			return err
+		}
+		_, err = q.Exec(
+			"INSERT INTO article_tags (article_id, tag_id, position) VALUES (?, ?, ?)",
+			articleID, tagID, position,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ensureTag(q execer, name string) (int, error) {
+	if _, err := q.Exec("INSERT INTO tags (name) VALUES (?) ON CONFLICT (name) DO NOTHING", name); err != nil {
+		return 0, err
+	}
+	var id int
+	err := q.QueryRow("SELECT id FROM tags WHERE name = ?", name).Scan(&id)
+	return id, err
+}
+
+func getArticleTags(db *sql.DB, articleID int) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT t.name FROM article_tags at
+		JOIN tags t ON t.id = at.tag_id
+		WHERE at.article_id = ?
+		ORDER BY at.position, t.name
+	`, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, name)
+	}
+	return tags, rows.Err()
+}
+
+// loadTags 批量加载文章列表的标签
+func (r *ArticleRepository) loadTags(articles []model.Article) error {
+	index := make(map[int]*model.Article, len(articles))
+	for i := range articles {
+		articles[i].Tags = []string{}
+		index[articles[i].ID] = &articles[i]
+	}
+
+	for start := 0; start < len(articles); start += tagLoadBatchSize {
+		end := start + tagLoadBatchSize
+		if end > len(articles) {
+			end = len(articles)
+		}
+
+		q := &queryArgs{dbType: r.dbType}
+		placeholders := make([]string, 0, end-start)
+		for _, article := range articles[start:end] {
+			placeholders = append(placeholders, q.add(article.ID))
+		}
+
+		rows, err := r.db.Query(`
+			SELECT at.article_id, t.name FROM article_tags at
+			JOIN tags t ON t.id = at.tag_id
+			WHERE at.article_id IN (`+strings.Join(placeholders, ", ")+`)
+			ORDER BY at.article_id, at.position, t.name
+		`, q.args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var articleID int
+			var name string
+			if err := rows.Scan(&articleID, &name); err != nil {
+				rows.Close()
+				return err
+			}
+			if article, ok := index[articleID]; ok {
+				article.Tags = append(article.Tags, name)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type TagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// GetPublishedCounts 返回至少有一篇已发布文章的标签及文章数，按文章数降序排列
+func (r *TagRepository) GetPublishedCounts() ([]model.TagCount, error) {
+	rows, err := r.db.Query(`
+		SELECT t.id, t.name, COUNT(*) AS article_count
+		FROM tags t
+		JOIN article_tags at ON at.tag_id = t.id
+		JOIN articles a ON a.id = at.article_id
+		WHERE a.status = 'published' AND a.deleted_at IS NULL AND ` + listedVisibility + `
+		GROUP BY t.id, t.name
+		ORDER BY article_count DESC, t.name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.TagCount{}
+	for rows.Next() {
+		var tag model.TagCount
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+const tagSelect = `
+	SELECT t.id, t.name, t.created_at,
+		   COUNT(a.id),
+		   COALESCE(SUM(CASE WHEN a.status = 'published' THEN 1 ELSE 0 END), 0)
+	FROM tags t
+	LEFT JOIN article_tags at ON at.tag_id = t.id
+	LEFT JOIN articles a ON a.id = at.article_id AND a.deleted_at IS NULL
+`
+
+func scanTag(scanner interface{ Scan(...interface{}) error }) (*model.Tag, error) {
+	tag := &model.Tag{}
+	err := scanner.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.ArticleCount, &tag.PublishedCount)
+	return tag, err
+}
+
+// GetAll 返回全部标签（包括未被使用的），供后台管理
+func (r *TagRepository) GetAll() ([]model.Tag, error) {
+	rows, err := r.db.Query(tagSelect + `
+		GROUP BY t.id, t.name, t.created_at
+		ORDER BY t.name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *TagRepository) GetByID(id int) (*model.Tag, error) {
+	tag, err := scanTag(r.db.QueryRow(tagSelect+`
+		WHERE t.id = ?
+		GROUP BY t.id, t.name, t.created_at
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tag not found")
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (r *TagRepository) GetIDByName(name string) (int, error) {
+	var id int
+	err := r.db.QueryRow("SELECT id FROM tags WHERE name = ?", name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (r *TagRepository) Rename(id int, name string) error {
+	_, err := r.db.Exec("UPDATE tags SET name = ? WHERE id = ?", name, id)
+	return err
+}
+
+// Merge 把 sourceIDs 的文章关联转移到 targetID 并删除源标签，返回受影响的文章数
+func (r *TagRepository) Merge(sourceIDs []int, targetID int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	affected := 0
+	for _, sourceID := range sourceIDs {
+		// 文章已带有目标标签时只删除源标签的关联
+		result, err := tx.Exec(`
+			INSERT INTO article_tags (article_id, tag_id, position)
+			SELECT article_id, ?, position FROM article_tags
+			WHERE tag_id = ? AND article_id NOT IN (SELECT article_id FROM article_tags WHERE tag_id = ?)
+		`, targetID, sourceID, targetID)
+		if err != nil {
+			return 0, err
+		}
+		moved, _ := result.RowsAffected()
+		affected += int(moved)
+
+		if _, err := tx.Exec("DELETE FROM article_tags WHERE tag_id = ?", sourceID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("DELETE FROM tags WHERE id = ?", sourceID); err != nil {
+			return 0, err
+		}
+	}
+
+	return affected, tx.Commit()
+}
+
+// Delete 删除标签并从所有文章上移除，返回受影响的文章数
+func (r *TagRepository) Delete(id int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM article_tags WHERE tag_id = ?", id)
+	if err != nil {
+		return 0, err
+	}
+	affected, _ := result.RowsAffected()
+
+	if _, err := tx.Exec("DELETE FROM tags WHERE id = ?", id); err != nil {
+		return 0, err
+	}
+	return int(affected), tx.Commit()
+}
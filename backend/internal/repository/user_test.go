@@ -0,0 +1,49 @@
+package repository
+
+import "testing"
+
+func TestFindOrCreateImportedUserTx(t *testing.T) {
+	articles := newTestArticleRepository(t)
+	users := NewUserRepository(articles.db)
+	tx, err := articles.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	// 迁移创建的管理员使用 admin@example.com，导入的评论不能归到管理员名下
+	id, err := users.FindOrCreateImportedUserTx(tx, "admin", "Admin@Example.com")
+	if err != nil {
+		t.Fatalf("admin email: %v", err)
+	}
+	if id == 1 {
+		t.Fatalf("imported comment author reused the admin account")
+	}
+	var role, email string
+	if err := tx.QueryRow("SELECT role, email FROM users WHERE id = ?", id).Scan(&role, &email); err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if role != "guest" || email == "admin@example.com" {
+		t.Errorf("imported user = %q <%s>, want a guest with a placeholder email", role, email)
+	}
+
+	again, err := users.FindOrCreateImportedUserTx(tx, "admin", "admin@example.com")
+	if err != nil {
+		t.Fatalf("admin email again: %v", err)
+	}
+	if again != id {
+		t.Errorf("second import = %d, want the same imported user %d", again, id)
+	}
+
+	guest, err := users.FindOrCreateImportedUserTx(tx, "Reader", "reader@example.com")
+	if err != nil {
+		t.Fatalf("guest: %v", err)
+	}
+	reused, err := users.FindOrCreateImportedUserTx(tx, "Reader 2", "reader@example.com")
+	if err != nil {
+		t.Fatalf("guest again: %v", err)
+	}
+	if reused != guest {
+		t.Errorf("guest email = %d, want the existing guest %d", reused, guest)
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"pea-blog-backend/internal/model"
+)
+
+func TestUpdateVersionConflict(t *testing.T) {
+	r := newTestArticleRepository(t)
+	created := createTestArticle(t, r, "Versioned")
+
+	first, err := r.GetByIDForEdit(created.ID)
+	if err != nil {
+		t.Fatalf("load first: %v", err)
+	}
+	second, err := r.GetByIDForEdit(created.ID)
+	if err != nil {
+		t.Fatalf("load second: %v", err)
+	}
+
+	first.Content = "first edit"
+	if err := r.UpdateWithRevision(first, &model.ArticleRevision{ArticleID: created.ID, Title: "Versioned", Content: "content"}); err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	if first.Version != created.Version+1 {
+		t.Errorf("version = %d, want %d", first.Version, created.Version+1)
+	}
+
+	// 第二个编辑者基于旧版本保存，不能覆盖第一个人的修改，也不能留下历史版本
+	second.Content = "second edit"
+	err = r.UpdateWithRevision(second, &model.ArticleRevision{ArticleID: created.ID, Title: "Versioned", Content: "content"})
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("stale update error = %v, want %v", err, ErrVersionConflict)
+	}
+
+	current, err := r.GetByIDForEdit(created.ID)
+	if err != nil {
+		t.Fatalf("load current: %v", err)
+	}
+	if current.Content != "first edit" || current.Version != first.Version {
+		t.Errorf("current = %q v%d, want %q v%d", current.Content, current.Version, "first edit", first.Version)
+	}
+	var revisions int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM article_revisions WHERE article_id = ?", created.ID).Scan(&revisions); err != nil {
+		t.Fatalf("count revisions: %v", err)
+	}
+	if revisions != 1 {
+		t.Errorf("revisions = %d, want 1", revisions)
+	}
+}
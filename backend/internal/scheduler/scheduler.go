@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"pea-blog-backend/internal/config"
 	"pea-blog-backend/internal/service"
 	"pea-blog-backend/pkg/logger"
 	"time"
@@ -8,12 +9,18 @@ import (
 
 type Scheduler struct {
 	articleService *service.ArticleService
+	mediaService   *service.MediaService
+	mediaConfig    *config.MediaConfig
+	trashConfig    *config.TrashConfig
 	logger         *logger.Logger
 }
 
-func New(articleService *service.ArticleService, logger *logger.Logger) *Scheduler {
+func New(articleService *service.ArticleService, mediaService *service.MediaService, mediaConfig *config.MediaConfig, trashConfig *config.TrashConfig, logger *logger.Logger) *Scheduler {
 	return &Scheduler{
 		articleService: articleService,
+		mediaService:   mediaService,
+		mediaConfig:    mediaConfig,
+		trashConfig:    trashConfig,
 		logger:         logger,
 	}
 }
@@ -22,13 +29,88 @@ func (s *Scheduler) Start() {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		s.logger.Debug("Scheduler checking for articles to publish...")
-		errors := s.articleService.PublishScheduledArticles()
-		if len(errors) > 0 {
-			for _, err := range errors {
-				s.logger.Error("Failed to publish scheduled article", "error", err)
-			}
+	// 孤立媒体检查间隔较长，单独计时
+	var mediaTicks <-chan time.Time
+	if s.mediaConfig.OrphanAction == "report" || s.mediaConfig.OrphanAction == "remove" {
+		mediaTicker := time.NewTicker(s.mediaConfig.CleanupInterval)
+		defer mediaTicker.Stop()
+		mediaTicks = mediaTicker.C
+	}
+
+	var trashTicks <-chan time.Time
+	if s.trashConfig.Retention > 0 {
+		trashTicker := time.NewTicker(s.trashConfig.PurgeInterval)
+		defer trashTicker.Stop()
+		trashTicks = trashTicker.C
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			s.publishScheduledArticles()
+			s.expireArticles()
+			s.unpinExpiredArticles()
+		case <-mediaTicks:
+			s.cleanupOrphanedMedia()
+		case <-trashTicks:
+			s.purgeTrash()
+		}
+	}
+}
+
+func (s *Scheduler) publishScheduledArticles() {
+	s.logger.Debug("Scheduler checking for articles to publish...")
+	errors := s.articleService.PublishScheduledArticles()
+	if len(errors) > 0 {
+		for _, err := range errors {
+			s.logger.Error("Failed to publish scheduled article", "error", err)
 		}
 	}
 }
+
+func (s *Scheduler) expireArticles() {
+	for _, err := range s.articleService.ExpireArticles() {
+		s.logger.Error("Failed to expire article", "error", err)
+	}
+}
+
+func (s *Scheduler) unpinExpiredArticles() {
+	if err := s.articleService.UnpinExpiredArticles(); err != nil {
+		s.logger.Error("Failed to unpin expired articles", "error", err)
+	}
+}
+
+func (s *Scheduler) cleanupOrphanedMedia() {
+	if s.mediaConfig.OrphanAction == "remove" {
+		removed, errors := s.mediaService.RemoveOrphans(s.mediaConfig.OrphanGracePeriod)
+		for _, err := range errors {
+			s.logger.Error("Failed to remove orphaned media", "error", err)
+		}
+		if removed > 0 {
+			s.logger.Info("Removed orphaned media", "count", removed)
+		}
+		return
+	}
+
+	orphans, err := s.mediaService.FindOrphans(s.mediaConfig.OrphanGracePeriod)
+	if err != nil {
+		s.logger.Error("Failed to find orphaned media", "error", err)
+		return
+	}
+	for _, media := range orphans {
+		s.logger.Warn("Orphaned media found", "id", media.ID, "file", media.FileName, "size", media.Size, "created_at", media.CreatedAt)
+	}
+	if len(orphans) > 0 {
+		s.logger.Info("Orphaned media report", "count", len(orphans))
+	}
+}
+
+func (s *Scheduler) purgeTrash() {
+	purged, errors := s.articleService.PurgeTrash(s.trashConfig.Retention)
+	for _, err := range errors {
+		s.logger.Error("Failed to purge trashed article", "error", err)
+	}
+	if purged > 0 {
+		s.logger.Info("Purged trashed articles", "count", purged)
+	}
+}
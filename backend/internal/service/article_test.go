@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"pea-blog-backend/internal/model"
+)
+
+func TestUpdateArticleVersionConflict(t *testing.T) {
+	s := newTestService(t)
+	article, err := s.Article.CreateArticle(model.CreateArticleRequest{Title: "Draft", Content: "v1", Status: "draft"}, 1)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	stale := article.Version
+
+	if _, err := s.Article.UpdateArticle(article.ID, model.UpdateArticleRequest{Content: strPtr("v2")}, 1); err == nil || err.Error() != "version is required" {
+		t.Errorf("update without version = %v, want version is required", err)
+	}
+
+	updated, err := s.Article.UpdateArticle(article.ID, model.UpdateArticleRequest{Content: strPtr("v2"), Version: &stale}, 1)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Version != stale+1 {
+		t.Errorf("version = %d, want %d", updated.Version, stale+1)
+	}
+
+	_, err = s.Article.UpdateArticle(article.ID, model.UpdateArticleRequest{Content: strPtr("v3"), Version: &stale}, 1)
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("stale update error = %v, want *VersionConflictError", err)
+	}
+	// 冲突时返回当前内容，供前端展示差异
+	if conflict.Current.Content != "v2" || conflict.Current.Version != updated.Version {
+		t.Errorf("conflict current = %q v%d, want %q v%d", conflict.Current.Content, conflict.Current.Version, "v2", updated.Version)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/util"
+)
+
+// loginAdmin 以迁移创建的管理员登录，返回登录结果和会话 ID
+func loginAdmin(t *testing.T, s *Service) (*model.LoginResponse, string) {
+	t.Helper()
+	resp, err := s.Auth.Login(model.LoginRequest{Username: "admin", Password: "password"}, "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	claims, err := util.ValidateJWT(resp.Token)
+	if err != nil {
+		t.Fatalf("validate token: %v", err)
+	}
+	return resp, claims.ID
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+	s := newTestService(t)
+	first, sessionID := loginAdmin(t, s)
+
+	second, err := s.Auth.RefreshToken(model.RefreshTokenRequest{RefreshToken: first.RefreshToken}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	claims, err := util.ValidateJWT(second.Token)
+	if err != nil {
+		t.Fatalf("validate refreshed token: %v", err)
+	}
+	if claims.ID != sessionID {
+		t.Errorf("refreshed session = %q, want %q", claims.ID, sessionID)
+	}
+
+	// 旧 token 再次出现视为泄露，整个家族（会话）都被吊销
+	if _, err := s.Auth.RefreshToken(model.RefreshTokenRequest{RefreshToken: first.RefreshToken}, "127.0.0.1"); err == nil {
+		t.Fatal("reused refresh token was accepted")
+	}
+	if _, err := s.Auth.RefreshToken(model.RefreshTokenRequest{RefreshToken: second.RefreshToken}, "127.0.0.1"); err == nil {
+		t.Error("latest refresh token still works after reuse was detected")
+	}
+	if err := s.Auth.ValidateSession(1, sessionID); err == nil || err.Error() != "session revoked" {
+		t.Errorf("ValidateSession after reuse = %v, want session revoked", err)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+	s := newTestService(t)
+	revoked, revokedID := loginAdmin(t, s)
+	_, keptID := loginAdmin(t, s)
+
+	if err := s.Auth.RevokeSession(2, revokedID); err == nil || err.Error() != "session not found" {
+		t.Errorf("revoking another user's session = %v, want session not found", err)
+	}
+	if err := s.Auth.RevokeSession(1, revokedID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+
+	if err := s.Auth.ValidateSession(1, revokedID); err == nil {
+		t.Error("revoked session is still valid")
+	}
+	if _, err := s.Auth.RefreshToken(model.RefreshTokenRequest{RefreshToken: revoked.RefreshToken}, "127.0.0.1"); err == nil {
+		t.Error("refresh token of a revoked session was accepted")
+	}
+	if err := s.Auth.ValidateSession(1, keptID); err != nil {
+		t.Errorf("other session = %v, want valid", err)
+	}
+
+	sessions, err := s.Auth.GetSessions(1, keptID)
+	if err != nil {
+		t.Fatalf("get sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != keptID || !sessions[0].Current {
+		t.Errorf("sessions = %+v, want only the current session %s", sessions, keptID)
+	}
+
+	count, err := s.Auth.RevokeAllSessions(1)
+	if err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("revoked = %d, want 1", count)
+	}
+	if err := s.Auth.ValidateSession(1, keptID); err == nil {
+		t.Error("session is still valid after revoking all sessions")
+	}
+}
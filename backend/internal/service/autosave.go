@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+
+	"pea-blog-backend/internal/model"
+)
+
+// SaveAutosave 保存用户编辑中的内容，不修改文章本身。articleID 为 0 表示尚未创建的新文章
+func (s *ArticleService) SaveAutosave(userID, articleID int, req model.AutosaveRequest) (*model.ArticleAutosave, error) {
+	autosave := &model.ArticleAutosave{
+		ArticleID:  articleID,
+		UserID:     userID,
+		Title:      req.Title,
+		Content:    req.Content,
+		Summary:    req.Summary,
+		Tags:       req.Tags,
+		CoverImage: req.CoverImage,
+	}
+
+	if articleID != 0 {
+		article, err := s.articleRepo.GetByIDForEdit(articleID)
+		if err != nil {
+			return nil, fmt.Errorf("article not found")
+		}
+		autosave.BaseVersion = req.BaseVersion
+		if autosave.BaseVersion == 0 {
+			// 未指定时保持第一次自动保存时的版本，之后别人的修改才能被发现
+			existing, err := s.articleRepo.GetAutosave(userID, articleID)
+			if err == nil {
+				autosave.BaseVersion = existing.BaseVersion
+			} else {
+				autosave.BaseVersion = article.Version
+			}
+		}
+	}
+
+	if err := s.articleRepo.SaveAutosave(autosave); err != nil {
+		s.logger.Error("Failed to save autosave", "userID", userID, "articleID", articleID, "error", err)
+		return nil, fmt.Errorf("failed to save autosave")
+	}
+	return s.GetAutosave(userID, articleID)
+}
+
+func (s *ArticleService) GetAutosave(userID, articleID int) (*model.ArticleAutosave, error) {
+	var article *model.Article
+	if articleID != 0 {
+		var err error
+		if article, err = s.articleRepo.GetByIDForEdit(articleID); err != nil {
+			return nil, fmt.Errorf("article not found")
+		}
+	}
+
+	autosave, err := s.articleRepo.GetAutosave(userID, articleID)
+	if err != nil {
+		if err.Error() == "autosave not found" {
+			return nil, err
+		}
+		s.logger.Error("Failed to get autosave", "userID", userID, "articleID", articleID, "error", err)
+		return nil, fmt.Errorf("failed to get autosave")
+	}
+	if article != nil {
+		autosave.Stale = autosave.BaseVersion != article.Version
+	}
+	return autosave, nil
+}
+
+func (s *ArticleService) DeleteAutosave(userID, articleID int) error {
+	err := s.articleRepo.DeleteAutosave(userID, articleID)
+	if err != nil {
+		if err.Error() == "autosave not found" {
+			return err
+		}
+		s.logger.Error("Failed to delete autosave", "userID", userID, "articleID", articleID, "error", err)
+		return fmt.Errorf("failed to delete autosave")
+	}
+	return nil
+}
+
+// PromoteAutosave 把自动保存提交为正式内容：新文章创建为文章，已有文章按普通更新处理（记录历史版本、检查版本冲突）。
+// 成功后删除自动保存
+func (s *ArticleService) PromoteAutosave(userID, articleID int, req model.PromoteAutosaveRequest) (*model.Article, error) {
+	autosave, err := s.GetAutosave(userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var article *model.Article
+	if articleID == 0 {
+		if autosave.Title == "" || autosave.Content == "" {
+			return nil, fmt.Errorf("autosave is incomplete: title and content are required")
+		}
+		status := req.Status
+		if status == "" {
+			status = "draft"
+		}
+		article, err = s.CreateArticle(model.CreateArticleRequest{
+			Title:       autosave.Title,
+			Content:     autosave.Content,
+			Summary:     autosave.Summary,
+			Tags:        autosave.Tags,
+			Status:      status,
+			CoverImage:  autosave.CoverImage,
+			PublishedAt: req.PublishedAt,
+		}, userID)
+	} else {
+		if autosave.Title == "" || autosave.Content == "" {
+			return nil, fmt.Errorf("autosave is incomplete: title and content are required")
+		}
+		update := model.UpdateArticleRequest{
+			Title:       &autosave.Title,
+			Content:     &autosave.Content,
+			Summary:     &autosave.Summary,
+			Tags:        autosave.Tags,
+			CoverImage:  autosave.CoverImage,
+			PublishedAt: req.PublishedAt,
+			Version:     req.Version,
+		}
+		if req.Status != "" {
+			update.Status = &req.Status
+		}
+		if update.Version == nil {
+			update.Version = &autosave.BaseVersion
+		}
+		article, err = s.UpdateArticle(articleID, update, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.articleRepo.DeleteAutosave(userID, articleID); err != nil {
+		s.logger.Warn("Failed to delete promoted autosave", "userID", userID, "articleID", articleID, "error", err)
+	}
+	s.logger.Info("Autosave promoted", "userID", userID, "articleID", article.ID)
+	return article, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/repository"
+	"pea-blog-backend/pkg/logger"
+)
+
+type CategoryService struct {
+	categoryRepo *repository.CategoryRepository
+	logger       *logger.Logger
+}
+
+func NewCategoryService(categoryRepo *repository.CategoryRepository, logger *logger.Logger) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		logger:       logger,
+	}
+}
+
+// GetCategoryTree 返回分类树。includeUnpublished 为 false 时文章数只统计已发布的文章
+func (s *CategoryService) GetCategoryTree(includeUnpublished bool) ([]*model.Category, error) {
+	categories, err := s.categoryRepo.GetAll(!includeUnpublished)
+	if err != nil {
+		s.logger.Error("Failed to get categories", "error", err)
+		return nil, fmt.Errorf("failed to get categories")
+	}
+
+	nodes := make(map[int]*model.Category, len(categories))
+	for i := range categories {
+		categories[i].Children = []*model.Category{}
+		nodes[categories[i].ID] = &categories[i]
+	}
+
+	roots := []*model.Category{}
+	for i := range categories {
+		category := &categories[i]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+
+	for _, root := range roots {
+		sumTotalCount(root)
+	}
+	return roots, nil
+}
+
+// sumTotalCount 计算包括所有子分类在内的文章数
+func sumTotalCount(category *model.Category) int {
+	category.TotalCount = category.ArticleCount
+	for _, child := range category.Children {
+		category.TotalCount += sumTotalCount(child)
+	}
+	return category.TotalCount
+}
+
+func (s *CategoryService) CreateCategory(req *model.CategoryRequest) (*model.Category, error) {
+	category, err := s.prepare(0, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Create(category); err != nil {
+		s.logger.Error("Failed to create category", "name", category.Name, "error", err)
+		return nil, fmt.Errorf("failed to create category")
+	}
+
+	s.logger.Info("Category created", "id", category.ID, "slug", category.Slug)
+	return s.getCategory(category.ID)
+}
+
+func (s *CategoryService) UpdateCategory(id int, req *model.CategoryRequest) (*model.Category, error) {
+	category, err := s.prepare(id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Update(category); err != nil {
+		if err.Error() == "category not found" {
+			return nil, err
+		}
+		s.logger.Error("Failed to update category", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to update category")
+	}
+
+	s.logger.Info("Category updated", "id", id, "slug", category.Slug)
+	return s.getCategory(id)
+}
+
+// DeleteCategory 删除分类，子分类和文章归入上一级分类
+func (s *CategoryService) DeleteCategory(id int) error {
+	if err := s.categoryRepo.Delete(id); err != nil {
+		if err.Error() == "category not found" {
+			return err
+		}
+		s.logger.Error("Failed to delete category", "id", id, "error", err)
+		return fmt.Errorf("failed to delete category")
+	}
+
+	s.logger.Info("Category deleted", "id", id)
+	return nil
+}
+
+func (s *CategoryService) getCategory(id int) (*model.Category, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return nil, err
+		}
+		s.logger.Error("Failed to get category", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get category")
+	}
+	category.Children = []*model.Category{}
+	return category, nil
+}
+
+// prepare 校验上级分类：上级分类必须存在，且不能是分类自身或其子孙分类
+func (s *CategoryService) prepare(id int, req *model.CategoryRequest) (*model.Category, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("category name is required")
+	}
+
+	if id != 0 {
+		if _, err := s.getCategory(id); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ParentID != nil {
+		categories, err := s.categoryRepo.GetAll(false)
+		if err != nil {
+			s.logger.Error("Failed to get categories", "error", err)
+			return nil, fmt.Errorf("failed to get categories")
+		}
+		parents := make(map[int]*int, len(categories))
+		for _, category := range categories {
+			parents[category.ID] = category.ParentID
+		}
+
+		if _, ok := parents[*req.ParentID]; !ok {
+			return nil, fmt.Errorf("parent category not found")
+		}
+		// 沿上级链向上查找，遇到自身说明会形成环
+		ancestor := req.ParentID
+		for depth := 0; id != 0 && ancestor != nil && depth <= len(parents); depth++ {
+			if *ancestor == id {
+				return nil, fmt.Errorf("invalid parent category")
+			}
+			ancestor = parents[*ancestor]
+		}
+	}
+
+	return &model.Category{
+		ID:          id,
+		Name:        name,
+		Slug:        strings.TrimSpace(req.Slug),
+		Description: strings.TrimSpace(req.Description),
+		ParentID:    req.ParentID,
+	}, nil
+}
+
+// resolveCategory 校验文章的分类。categoryID 为空或为 0 时返回 nil，表示未分类
+func (s *ArticleService) resolveCategory(categoryID *int) (*model.ArticleCategory, error) {
+	if categoryID == nil || *categoryID == 0 {
+		return nil, nil
+	}
+
+	category, err := s.categoryRepo.GetByID(*categoryID)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return nil, err
+		}
+		s.logger.Error("Failed to get category", "id", *categoryID, "error", err)
+		return nil, fmt.Errorf("failed to get category")
+	}
+	return &model.ArticleCategory{ID: category.ID, Name: category.Name, Slug: category.Slug}, nil
+}
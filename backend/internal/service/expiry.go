@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"pea-blog-backend/internal/model"
+)
+
+// validateExpiry 检查到期时间晚于当前时间和发布时间
+func validateExpiry(publishedAt, expiresAt *time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+	if !expiresAt.After(time.Now()) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+	if publishedAt != nil && !expiresAt.After(*publishedAt) {
+		return fmt.Errorf("expires_at must be after published_at")
+	}
+	return nil
+}
+
+// checkUpdatedExpiry 用更新后的发布时间与到期时间校验，只修改发布时间时与原有到期时间比较
+func (s *ArticleService) checkUpdatedExpiry(id int, req model.UpdateArticleRequest) error {
+	if req.ClearExpiresAt || (req.ExpiresAt == nil && req.PublishedAt == nil) {
+		return nil
+	}
+	if req.ExpiresAt != nil && req.PublishedAt != nil {
+		return validateExpiry(req.PublishedAt, req.ExpiresAt)
+	}
+
+	article, err := s.articleRepo.GetByIDForEdit(id)
+	if err != nil {
+		return fmt.Errorf("article not found")
+	}
+	publishedAt, expiresAt := article.PublishedAt, article.ExpiresAt
+	if req.PublishedAt != nil {
+		publishedAt = req.PublishedAt
+	}
+	if req.ExpiresAt != nil {
+		return validateExpiry(publishedAt, req.ExpiresAt)
+	}
+	if expiresAt != nil && publishedAt != nil && !expiresAt.After(*publishedAt) {
+		return fmt.Errorf("expires_at must be after published_at")
+	}
+	return nil
+}
+
+// ExpireArticles 下线到期的文章，由定时任务调用
+func (s *ArticleService) ExpireArticles() []error {
+	articles, err := s.articleRepo.GetExpiredArticles(time.Now())
+	if err != nil {
+		return []error{err}
+	}
+
+	var errors []error
+	for _, article := range articles {
+		if err := s.articleRepo.Expire(article.ID, article.ExpiryAction); err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		s.logger.Info("Article expired", "articleID", article.ID, "action", article.ExpiryAction, "expiresAt", article.ExpiresAt)
+	}
+	return errors
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/transfer"
+)
+
+const (
+	ImportActionCreated     = "created"
+	ImportActionOverwritten = "overwritten"
+	ImportActionRenamed     = "renamed"
+	ImportActionSkipped     = "skipped"
+	ImportActionFailed      = "failed"
+)
+
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictRename    = "rename"
+)
+
+// ErrImportFailed 表示至少一篇文章无法导入，整个事务已回滚
+var ErrImportFailed = errors.New("import failed, no articles were imported")
+
+// ImportArticles 在单个事务中导入文章。dry-run 时执行全部检查与写入后回滚，
+// 只返回报告；任意一篇失败时同样回滚，避免导入一半。
+func (s *ArticleService) ImportArticles(entries []transfer.Entry, opts model.ImportOptions, importerID int) (*model.ImportReport, error) {
+	if opts.OnConflict == "" {
+		opts.OnConflict = ConflictSkip
+	}
+
+	importer, err := s.userRepo.GetByID(importerID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	report := &model.ImportReport{
+		DryRun:     opts.DryRun,
+		OnConflict: opts.OnConflict,
+		Total:      len(entries),
+		Items:      []model.ImportResult{},
+	}
+
+	tx, err := s.articleRepo.Begin()
+	if err != nil {
+		s.logger.Error("Failed to begin import transaction", "error", err)
+		return nil, fmt.Errorf("failed to import articles")
+	}
+	defer tx.Rollback()
+
+	authors := map[string]int{"": importer.ID}
+	for _, entry := range entries {
+		article := entry.Article
+		result := model.ImportResult{Source: entry.Source, Title: article.Title}
+
+		warnings, err := normalizeImportedArticle(&article)
+		if err != nil {
+			result.Action = ImportActionFailed
+			result.Message = err.Error()
+			addImportResult(report, result)
+			continue
+		}
+		result.Title = article.Title
+
+		authorID, ok := authors[entry.Author]
+		if !ok {
+			authorID = importer.ID
+			if author, err := s.userRepo.GetByUsername(entry.Author); err == nil {
+				authorID = author.ID
+			}
+			authors[entry.Author] = authorID
+		}
+		article.AuthorID = authorID
+		if authorID == importer.ID {
+			result.Author = importer.Username
+			if entry.Author != "" && entry.Author != importer.Username {
+				appendImportMessage(&result, fmt.Sprintf("author %q not found, assigned to %s", entry.Author, importer.Username))
+			}
+		} else {
+			result.Author = entry.Author
+		}
+
+		// 每篇文章在单独的保存点中写入：Postgres 中语句出错会中止整个事务，
+		// 回滚到保存点后才能继续检查后面的文章，报告中的结果才准确
+		if _, err := tx.Exec("SAVEPOINT import_entry"); err != nil {
+			s.logger.Error("Failed to create import savepoint", "error", err)
+			return nil, fmt.Errorf("failed to import articles")
+		}
+		s.importEntry(tx, entry, &article, warnings, opts.OnConflict, &result)
+		release := "RELEASE SAVEPOINT import_entry"
+		if result.Action == ImportActionFailed {
+			release = "ROLLBACK TO SAVEPOINT import_entry"
+		}
+		if _, err := tx.Exec(release); err != nil {
+			s.logger.Error("Failed to release import savepoint", "error", err)
+			return nil, fmt.Errorf("failed to import articles")
+		}
+		report.Comments += result.Comments
+
+		addImportResult(report, result)
+	}
+
+	if report.Failed > 0 {
+		return report, ErrImportFailed
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Failed to commit import", "error", err)
+		return nil, fmt.Errorf("failed to import articles")
+	}
+
+	s.logger.Info("Articles imported", "importerID", importerID, "created", report.Created,
+		"overwritten", report.Overwritten, "renamed", report.Renamed, "skipped", report.Skipped)
+	return report, nil
+}
+
+// importEntry 在当前保存点中写入一篇文章及其评论，结果写入 result
+func (s *ArticleService) importEntry(tx *sql.Tx, entry transfer.Entry, article *model.Article, warnings []string, onConflict string, result *model.ImportResult) {
+	existingID, err := s.articleRepo.GetIDByTitleTx(tx, article.Title)
+	if err != nil {
+		s.logger.Error("Failed to check article title", "title", article.Title, "error", err)
+		result.Action = ImportActionFailed
+		result.Message = "failed to check existing articles"
+		return
+	}
+
+	result.Action = ImportActionCreated
+	if existingID != 0 {
+		switch onConflict {
+		case ConflictSkip:
+			result.Action = ImportActionSkipped
+			result.ArticleID = existingID
+			result.Message = "an article with this title already exists"
+			return
+		case ConflictOverwrite:
+			article.ID = existingID
+			result.Action = ImportActionOverwritten
+		case ConflictRename:
+			title, err := s.uniqueImportTitle(tx, article.Title)
+			if err != nil {
+				result.Action = ImportActionFailed
+				result.Message = "failed to find a free title"
+				return
+			}
+			article.Title = title
+			result.Title = title
+			result.Action = ImportActionRenamed
+		}
+	}
+
+	if result.Action == ImportActionOverwritten {
+		err = s.articleRepo.OverwriteTx(tx, article)
+	} else {
+		// 覆盖时保留原有的分类、可见性等设置，规范化这些设置产生的警告只对新建的文章有意义
+		for _, warning := range warnings {
+			appendImportMessage(result, warning)
+		}
+		// 分类 ID 来自导出时的站点，在本站不存在时不设分类
+		if _, err := s.resolveCategory(article.CategoryID); err != nil {
+			if err.Error() != "category not found" {
+				result.Action = ImportActionFailed
+				result.Message = err.Error()
+				return
+			}
+			article.CategoryID = nil
+			appendImportMessage(result, "category not found, imported without a category")
+		}
+		err = s.articleRepo.CreateTx(tx, article)
+	}
+	if err != nil {
+		s.logger.Error("Failed to import article", "source", entry.Source, "error", err)
+		result.Action = ImportActionFailed
+		result.Message = "failed to save article"
+		return
+	}
+
+	result.ArticleID = article.ID
+
+	if len(entry.Comments) > 0 {
+		if result.Action == ImportActionOverwritten {
+			// 覆盖已有文章时不导入评论，避免重复
+			appendImportMessage(result, "comments were not imported for an overwritten article")
+		} else {
+			count, err := s.importComments(tx, article.ID, entry.Comments)
+			if err != nil {
+				s.logger.Error("Failed to import comments", "source", entry.Source, "error", err)
+				result.Action = ImportActionFailed
+				result.Message = "failed to import comments"
+				return
+			}
+			result.Comments = count
+		}
+	}
+}
+
+// uniqueImportTitle 为重名文章生成 "标题 (2)"、"标题 (3)" 形式的新标题
+func (s *ArticleService) uniqueImportTitle(tx *sql.Tx, title string) (string, error) {
+	for n := 2; n < 1000; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		candidate := truncateRunes(title, 200-utf8.RuneCountInString(suffix)) + suffix
+		id, err := s.articleRepo.GetIDByTitleTx(tx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if id == 0 {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free title for %q", title)
+}
+
+// importComments 按来源 ID 顺序写入评论，将来源系统中的父评论 ID 映射为新 ID 以保留回复关系
+func (s *ArticleService) importComments(tx *sql.Tx, articleID int, comments []transfer.Comment) (int, error) {
+	sorted := make([]transfer.Comment, len(comments))
+	copy(sorted, comments)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+
+	ids := map[int]int{}
+	count := 0
+	for _, c := range sorted {
+		if strings.TrimSpace(c.Content) == "" {
+			continue
+		}
+
+		authorID, err := s.userRepo.FindOrCreateImportedUserTx(tx, c.AuthorName, c.AuthorEmail)
+		if err != nil {
+			return 0, err
+		}
+
+		comment := &model.Comment{
+			Content:   c.Content,
+			AuthorID:  authorID,
+			ArticleID: articleID,
+			CreatedAt: c.CreatedAt,
+		}
+		if parentID, ok := ids[c.ParentKey]; ok && c.ParentKey != 0 {
+			comment.ParentID = &parentID
+		}
+
+		if err := s.commentRepo.CreateTx(tx, comment); err != nil {
+			return 0, err
+		}
+		ids[c.Key] = comment.ID
+		count++
+	}
+
+	if count > 0 {
+		if err := s.commentRepo.RefreshCountTx(tx, articleID); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// normalizeImportedArticle 校验并规范化导入的文章，返回需要在报告中提示的警告
+func normalizeImportedArticle(article *model.Article) ([]string, error) {
+	var warnings []string
+	article.Title = strings.TrimSpace(article.Title)
+	if article.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if utf8.RuneCountInString(article.Title) > 200 {
+		return nil, fmt.Errorf("title is longer than 200 characters")
+	}
+	if strings.TrimSpace(article.Content) == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	if article.Status == "" {
+		article.Status = "draft"
+	}
+	switch article.Status {
+	case "draft", "published":
+	case "scheduled":
+		if article.PublishedAt == nil {
+			return nil, fmt.Errorf("scheduled article requires published_at")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported status %q", article.Status)
+	}
+
+	// 导出文件不含密码哈希，受密码保护的文章导入后无法解锁，改为私密
+	switch article.Visibility {
+	case "", model.VisibilityPublic, model.VisibilityUnlisted, model.VisibilityPrivate:
+	case model.VisibilityPassword:
+		if article.PasswordHash == "" {
+			article.Visibility = model.VisibilityPrivate
+			warnings = append(warnings, "password-protected article was imported as private because the export contains no password; set a new password to protect it again")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported visibility %q", article.Visibility)
+	}
+
+	// 与 CreateArticle 使用相同的到期校验；已过期或早于发布时间的到期设置直接丢弃，不阻止导入
+	switch article.ExpiryAction {
+	case "", model.ExpiryUnpublish, model.ExpiryArchive:
+	default:
+		return nil, fmt.Errorf("unsupported expiry_action %q", article.ExpiryAction)
+	}
+	if err := validateExpiry(article.PublishedAt, article.ExpiresAt); err != nil {
+		article.ExpiresAt = nil
+		warnings = append(warnings, fmt.Sprintf("expiry was not imported: %s", err.Error()))
+	}
+	article.ExpiresAt = utcTime(article.ExpiresAt)
+
+	setSummary(article, article.Summary)
+	article.Summary = truncateRunes(strings.TrimSpace(article.Summary), 500)
+
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, tag := range article.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	article.Tags = tags
+
+	return warnings, nil
+}
+
+// appendImportMessage 把提示追加到导入结果的说明中，多条之间用分号分隔
+func appendImportMessage(result *model.ImportResult, message string) {
+	if result.Message == "" {
+		result.Message = message
+	} else {
+		result.Message += "; " + message
+	}
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
+
+func addImportResult(report *model.ImportReport, result model.ImportResult) {
+	switch result.Action {
+	case ImportActionCreated:
+		report.Created++
+	case ImportActionOverwritten:
+		report.Overwritten++
+	case ImportActionRenamed:
+		report.Renamed++
+	case ImportActionSkipped:
+		report.Skipped++
+	case ImportActionFailed:
+		report.Failed++
+	}
+	report.Items = append(report.Items, result)
+}
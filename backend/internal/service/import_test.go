@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/transfer"
+)
+
+func TestImportPasswordArticleRoundTrip(t *testing.T) {
+	s := newTestService(t)
+	original, err := s.Article.CreateArticle(model.CreateArticleRequest{
+		Title:      "Secret",
+		Content:    "hidden content",
+		Status:     "published",
+		Visibility: model.VisibilityPassword,
+		Password:   "hunter2",
+	}, 1)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	articles, err := s.Article.GetAllArticlesForExport()
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := transfer.WriteJSON(&buf, articles); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	entries, err := transfer.Parse("export.json", buf.Bytes())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	report, err := s.Article.ImportArticles(entries, model.ImportOptions{OnConflict: ConflictRename}, 1)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if report.Renamed != 1 || len(report.Items) != 1 {
+		t.Fatalf("report = %+v, want one renamed article", report)
+	}
+	item := report.Items[0]
+	if !strings.Contains(item.Message, "imported as private") {
+		t.Errorf("message = %q, want a warning about the password", item.Message)
+	}
+
+	imported, err := s.Article.articleRepo.GetByID(item.ArticleID)
+	if err != nil {
+		t.Fatalf("get imported: %v", err)
+	}
+	if imported.Visibility != model.VisibilityPrivate {
+		t.Errorf("visibility = %q, want %q", imported.Visibility, model.VisibilityPrivate)
+	}
+	if imported.ID == original.ID {
+		t.Errorf("import reused article %d, want a new article", original.ID)
+	}
+}
+
+func TestImportUnknownCategory(t *testing.T) {
+	s := newTestService(t)
+	category, err := s.Category.CreateCategory(&model.CategoryRequest{Name: "Go"})
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	missing := category.ID + 100
+	entries := []transfer.Entry{
+		{Source: "known", Article: model.Article{Title: "Known", Content: "text", Status: "published", CategoryID: &category.ID}},
+		{Source: "missing", Article: model.Article{Title: "Missing", Content: "text", Status: "published", CategoryID: &missing}},
+	}
+
+	report, err := s.Article.ImportArticles(entries, model.ImportOptions{}, 1)
+	if err != nil {
+		t.Fatalf("import: %v (%+v)", err, report)
+	}
+
+	known, err := s.Article.articleRepo.GetByID(report.Items[0].ArticleID)
+	if err != nil {
+		t.Fatalf("get known: %v", err)
+	}
+	if known.CategoryID == nil || *known.CategoryID != category.ID {
+		t.Errorf("known category = %v, want %d", known.CategoryID, category.ID)
+	}
+
+	imported, err := s.Article.articleRepo.GetByID(report.Items[1].ArticleID)
+	if err != nil {
+		t.Fatalf("get missing: %v", err)
+	}
+	if imported.CategoryID != nil {
+		t.Errorf("missing category = %d, want none", *imported.CategoryID)
+	}
+	if !strings.Contains(report.Items[1].Message, "category not found") {
+		t.Errorf("message = %q, want a category warning", report.Items[1].Message)
+	}
+}
+
+func TestImportExpiry(t *testing.T) {
+	s := newTestService(t)
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	entries := []transfer.Entry{
+		{Source: "future", Article: model.Article{Title: "Future", Content: "text", Status: "published", ExpiresAt: &future, ExpiryAction: model.ExpiryArchive}},
+		{Source: "past", Article: model.Article{Title: "Past", Content: "text", Status: "published", ExpiresAt: &past}},
+	}
+
+	report, err := s.Article.ImportArticles(entries, model.ImportOptions{}, 1)
+	if err != nil {
+		t.Fatalf("import: %v (%+v)", err, report)
+	}
+	kept, err := s.Article.articleRepo.GetByID(report.Items[0].ArticleID)
+	if err != nil {
+		t.Fatalf("get future: %v", err)
+	}
+	if kept.ExpiresAt == nil || kept.ExpiryAction != model.ExpiryArchive {
+		t.Errorf("future expiry = %v %q, want kept", kept.ExpiresAt, kept.ExpiryAction)
+	}
+	dropped, err := s.Article.articleRepo.GetByID(report.Items[1].ArticleID)
+	if err != nil {
+		t.Fatalf("get past: %v", err)
+	}
+	if dropped.ExpiresAt != nil {
+		t.Errorf("past expiry = %v, want dropped", dropped.ExpiresAt)
+	}
+	if !strings.Contains(report.Items[1].Message, "expiry was not imported") {
+		t.Errorf("message = %q, want an expiry warning", report.Items[1].Message)
+	}
+
+	invalid := []transfer.Entry{
+		{Source: "bad", Article: model.Article{Title: "Bad", Content: "text", Status: "published", ExpiresAt: &future, ExpiryAction: "delete"}},
+	}
+	if _, err := s.Article.ImportArticles(invalid, model.ImportOptions{}, 1); err != ErrImportFailed {
+		t.Errorf("unsupported expiry_action error = %v, want %v", err, ErrImportFailed)
+	}
+}
+
+func TestImportContinuesAfterFailedEntry(t *testing.T) {
+	s, db := newTestServiceDB(t)
+	// 用触发器模拟写入时的数据库错误
+	_, err := db.Exec(`CREATE TRIGGER reject_boom BEFORE INSERT ON articles WHEN NEW.title = 'Boom'
+		BEGIN SELECT RAISE(ABORT, 'boom'); END`)
+	if err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	entries := []transfer.Entry{
+		{Source: "boom", Article: model.Article{Title: "Boom", Content: "text", Status: "published"}},
+		{Source: "fine", Article: model.Article{Title: "Fine", Content: "text", Status: "published"}},
+	}
+
+	report, err := s.Article.ImportArticles(entries, model.ImportOptions{}, 1)
+	if err != ErrImportFailed {
+		t.Fatalf("import error = %v, want %v", err, ErrImportFailed)
+	}
+	if report.Failed != 1 || report.Created != 1 {
+		t.Errorf("report = %+v, want one failed and one created", report)
+	}
+	if report.Items[1].Action != ImportActionCreated {
+		t.Errorf("second entry = %q, want %q", report.Items[1].Action, ImportActionCreated)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM articles").Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("articles = %d, want the whole import rolled back", count)
+	}
+}
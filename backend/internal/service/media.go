@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/repository"
+	"pea-blog-backend/internal/storage"
+	"pea-blog-backend/pkg/logger"
+)
+
+type MediaService struct {
+	mediaRepo *repository.MediaRepository
+	storage   storage.Storage
+	logger    *logger.Logger
+}
+
+func NewMediaService(mediaRepo *repository.MediaRepository, store storage.Storage, logger *logger.Logger) *MediaService {
+	return &MediaService{
+		mediaRepo: mediaRepo,
+		storage:   store,
+		logger:    logger,
+	}
+}
+
+// FindDuplicate 按内容哈希查找已上传的相同文件，不存在时返回 nil
+func (s *MediaService) FindDuplicate(sha256 string) (*model.Media, error) {
+	media, err := s.mediaRepo.GetBySHA256(sha256)
+	if err != nil || media == nil {
+		return nil, err
+	}
+	s.present(media)
+	return media, nil
+}
+
+func (s *MediaService) CreateMedia(media *model.Media) error {
+	if err := s.mediaRepo.Create(media); err != nil {
+		return err
+	}
+	s.present(media)
+	return nil
+}
+
+func (s *MediaService) GetMedia(params model.MediaSearchParams) (*model.MediaListResponse, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > 100 {
+		params.PageSize = 20
+	}
+
+	media, total, err := s.mediaRepo.GetAll(params)
+	if err != nil {
+		s.logger.Error("Failed to get media from repository", "error", err)
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	for i := range media {
+		s.present(&media[i])
+	}
+
+	return &model.MediaListResponse{
+		Media:    media,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}, nil
+}
+
+func (s *MediaService) GetMediaByID(id int) (*model.Media, error) {
+	media, err := s.mediaRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	referenced, err := s.mediaRepo.IsReferenced(media.FileName)
+	if err != nil {
+		return nil, err
+	}
+	media.Referenced = &referenced
+	s.present(media)
+	return media, nil
+}
+
+func (s *MediaService) UpdateMedia(id int, req *model.UpdateMediaRequest) (*model.Media, error) {
+	if err := s.mediaRepo.UpdateAltText(id, req.AltText); err != nil {
+		return nil, err
+	}
+	return s.GetMediaByID(id)
+}
+
+// DeleteMedia 删除媒体记录及其全部文件；仍被文章引用时需要 force
+func (s *MediaService) DeleteMedia(id int, force bool) error {
+	media, err := s.mediaRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		referenced, err := s.mediaRepo.IsReferenced(media.FileName)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return fmt.Errorf("media is in use")
+		}
+	}
+
+	return s.remove(media)
+}
+
+// FindOrphans 返回上传超过 gracePeriod 且未被任何文章正文或封面引用的媒体。
+// 宽限期避免把刚上传、文章还未保存的图片当作孤立文件
+func (s *MediaService) FindOrphans(gracePeriod time.Duration) ([]model.Media, error) {
+	all, err := s.mediaRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-gracePeriod)
+	orphans := []model.Media{}
+	for _, media := range all {
+		if !media.CreatedAt.Before(cutoff) {
+			continue
+		}
+		referenced, err := s.mediaRepo.IsReferenced(media.FileName)
+		if err != nil {
+			return nil, err
+		}
+		if !referenced {
+			s.present(&media)
+			orphans = append(orphans, media)
+		}
+	}
+	return orphans, nil
+}
+
+// RemoveOrphans 删除孤立媒体，返回删除数量
+func (s *MediaService) RemoveOrphans(gracePeriod time.Duration) (int, []error) {
+	orphans, err := s.FindOrphans(gracePeriod)
+	if err != nil {
+		return 0, []error{err}
+	}
+
+	removed := 0
+	var errors []error
+	for i := range orphans {
+		if err := s.remove(&orphans[i]); err != nil {
+			errors = append(errors, fmt.Errorf("failed to remove media %d: %w", orphans[i].ID, err))
+			continue
+		}
+		removed++
+	}
+	return removed, errors
+}
+
+// UploadedImage 将媒体转换为上传接口的返回格式
+func (s *MediaService) UploadedImage(media *model.Media) *model.UploadedImage {
+	uploaded := &model.UploadedImage{
+		MediaID:  media.ID,
+		URL:      media.URL,
+		WebPURL:  media.WebPURL,
+		Width:    media.Width,
+		Height:   media.Height,
+		Variants: media.Variants,
+	}
+
+	if len(media.Variants) > 0 || media.WebPURL != "" {
+		var srcset, webpSrcset []string
+		for _, variant := range media.Variants {
+			srcset = append(srcset, fmt.Sprintf("%s %dw", variant.URL, variant.Width))
+			webpSrcset = append(webpSrcset, fmt.Sprintf("%s %dw", variant.WebPURL, variant.Width))
+		}
+		srcset = append(srcset, fmt.Sprintf("%s %dw", media.URL, media.Width))
+		webpSrcset = append(webpSrcset, fmt.Sprintf("%s %dw", media.WebPURL, media.Width))
+		uploaded.SrcSet = strings.Join(srcset, ", ")
+		// 未启用 cgo 构建时没有 WebP 版本
+		if media.WebPURL != "" {
+			uploaded.WebPSrcSet = strings.Join(webpSrcset, ", ")
+		}
+	}
+	return uploaded
+}
+
+// remove 先删除文件再删除记录，文件删除失败时保留记录以便重试
+func (s *MediaService) remove(media *model.Media) error {
+	ctx := context.Background()
+	for _, key := range mediaKeys(media) {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	if err := s.mediaRepo.Delete(media.ID); err != nil {
+		return err
+	}
+	s.logger.Info("Media removed", "id", media.ID, "file", media.FileName)
+	return nil
+}
+
+// present 根据存储后端填充访问 URL
+func (s *MediaService) present(media *model.Media) {
+	media.URL = s.storage.URL(media.FileName)
+	if media.WebPFileName != "" {
+		media.WebPURL = s.storage.URL(media.WebPFileName)
+	}
+	media.Variants = make([]model.ImageVariant, 0, len(media.Files))
+	for _, file := range media.Files {
+		variant := model.ImageVariant{Width: file.Width, Height: file.Height, URL: s.storage.URL(file.Key)}
+		if file.WebPKey != "" {
+			variant.WebPURL = s.storage.URL(file.WebPKey)
+		}
+		media.Variants = append(media.Variants, variant)
+	}
+}
+
+func mediaKeys(media *model.Media) []string {
+	keys := []string{media.FileName}
+	if media.WebPFileName != "" && media.WebPFileName != media.FileName {
+		keys = append(keys, media.WebPFileName)
+	}
+	for _, file := range media.Files {
+		keys = append(keys, file.Key)
+		if file.WebPKey != "" && file.WebPKey != file.Key {
+			keys = append(keys, file.WebPKey)
+		}
+	}
+	return keys
+}
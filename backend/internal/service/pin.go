@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"pea-blog-backend/internal/model"
+)
+
+// PinArticle 置顶或取消置顶文章，置顶截止时间必须晚于当前时间
+func (s *ArticleService) PinArticle(id int, req *model.PinArticleRequest) error {
+	var until *time.Time
+	if req.Pinned && req.PinnedUntil != nil {
+		if !req.PinnedUntil.After(time.Now()) {
+			return fmt.Errorf("pinned_until must be in the future")
+		}
+		until = utcTime(req.PinnedUntil)
+	}
+
+	if err := s.articleRepo.SetPinned(id, req.Pinned, until); err != nil {
+		if err.Error() == "article not found" {
+			return err
+		}
+		s.logger.Error("Failed to pin article", "articleID", id, "error", err)
+		return fmt.Errorf("failed to pin article")
+	}
+
+	s.logger.Info("Article pin changed", "articleID", id, "pinned", req.Pinned, "pinnedUntil", until)
+	return nil
+}
+
+func (s *ArticleService) FeatureArticle(id int, featured bool) error {
+	if err := s.articleRepo.SetFeatured(id, featured); err != nil {
+		if err.Error() == "article not found" {
+			return err
+		}
+		s.logger.Error("Failed to feature article", "articleID", id, "error", err)
+		return fmt.Errorf("failed to feature article")
+	}
+
+	s.logger.Info("Article featured changed", "articleID", id, "featured", featured)
+	return nil
+}
+
+// UnpinExpiredArticles 取消置顶时间已过的文章的置顶，由定时任务调用
+func (s *ArticleService) UnpinExpiredArticles() error {
+	count, err := s.articleRepo.UnpinExpired(time.Now())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		s.logger.Info("Expired pins removed", "count", count)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"pea-blog-backend/internal/model"
+)
+
+func TestPinExpiry(t *testing.T) {
+	s, db := newTestServiceDB(t)
+	ids := map[string]int{}
+	for _, title := range []string{"A expired", "B pinned", "C plain"} {
+		article, err := s.Article.CreateArticle(model.CreateArticleRequest{Title: title, Content: "content", Status: "published"}, 1)
+		if err != nil {
+			t.Fatalf("create %q: %v", title, err)
+		}
+		ids[title] = article.ID
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if err := s.Article.PinArticle(ids["A expired"], &model.PinArticleRequest{Pinned: true, PinnedUntil: &past}); err == nil {
+		t.Error("pinning with a past pinned_until succeeded")
+	}
+
+	future := time.Now().Add(time.Hour)
+	for _, title := range []string{"A expired", "B pinned"} {
+		if err := s.Article.PinArticle(ids[title], &model.PinArticleRequest{Pinned: true, PinnedUntil: &future}); err != nil {
+			t.Fatalf("pin %q: %v", title, err)
+		}
+	}
+	// 模拟置顶时间已过但定时任务尚未运行
+	if _, err := db.Exec("UPDATE articles SET pinned_until = ? WHERE id = ?", past.UTC(), ids["A expired"]); err != nil {
+		t.Fatalf("expire pin: %v", err)
+	}
+
+	list, err := s.Article.GetArticles(model.SearchParams{SortBy: "title", SortOrder: "asc"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var titles []string
+	for _, article := range list.Articles {
+		titles = append(titles, article.Title)
+	}
+	if len(titles) != 3 || titles[0] != "B pinned" || titles[1] != "A expired" {
+		t.Errorf("order = %v, want the unexpired pin first and the expired pin back in title order", titles)
+	}
+
+	if err := s.Article.UnpinExpiredArticles(); err != nil {
+		t.Fatalf("unpin expired: %v", err)
+	}
+	for title, want := range map[string]bool{"A expired": false, "B pinned": true} {
+		article, err := s.Article.articleRepo.GetByID(ids[title])
+		if err != nil {
+			t.Fatalf("get %q: %v", title, err)
+		}
+		if article.Pinned != want {
+			t.Errorf("%q pinned = %v, want %v", title, article.Pinned, want)
+		}
+		if !want && article.PinnedUntil != nil {
+			t.Errorf("%q pinned_until = %v, want cleared", title, article.PinnedUntil)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/util"
+)
+
+// DefaultPreviewTTL 为预览链接的默认有效期
+const DefaultPreviewTTL = 72 * time.Hour
+
+// CreatePreviewToken 为文章生成签名的预览令牌，令牌本身不落库，到期自动失效
+func (s *ArticleService) CreatePreviewToken(articleID int, ttl time.Duration) (*model.PreviewToken, error) {
+	article, err := s.articleRepo.GetByIDForEdit(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("article not found")
+	}
+
+	token, expiresAt, err := util.GeneratePreviewToken(article.ID, ttl)
+	if err != nil {
+		s.logger.Error("Failed to generate preview token", "articleID", articleID, "error", err)
+		return nil, fmt.Errorf("failed to generate preview token")
+	}
+
+	s.logger.Info("Preview token created", "articleID", articleID, "expiresAt", expiresAt)
+	return &model.PreviewToken{
+		ArticleID: article.ID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetArticlePreview 通过预览令牌获取文章，不论文章是否已发布，也不增加浏览量
+func (s *ArticleService) GetArticlePreview(token string) (*model.Article, error) {
+	articleID, err := util.ValidatePreviewToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired preview token")
+	}
+
+	article, err := s.articleRepo.GetByIDForEdit(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("article not found")
+	}
+	return article, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/util"
+)
+
+func TestGetArticlePreview(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+	s := newTestService(t)
+	draft, err := s.Article.CreateArticle(model.CreateArticleRequest{Title: "Draft", Content: "content", Status: "draft"}, 1)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	preview, err := s.Article.CreatePreviewToken(draft.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("create preview token: %v", err)
+	}
+	article, err := s.Article.GetArticlePreview(preview.Token)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if article.ID != draft.ID {
+		t.Errorf("preview article = %d, want %d", article.ID, draft.ID)
+	}
+
+	// 密码文章的访问令牌不能用来预览草稿
+	access, _, err := util.GenerateArticleAccessToken(draft.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("generate access token: %v", err)
+	}
+	if _, err := s.Article.GetArticlePreview(access); err == nil {
+		t.Error("article access token opened a draft preview")
+	}
+}
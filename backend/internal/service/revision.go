@@ -0,0 +1,317 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/repository"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// VersionConflictError 表示提交的版本号已过期，Current 为服务器上的最新内容
+type VersionConflictError struct {
+	Current *model.Article
+}
+
+func (e *VersionConflictError) Error() string {
+	return "version conflict"
+}
+
+// updateWithRevision 加载文章、应用 apply 的修改，并在同一事务中把修改前的内容保存为历史版本。
+// expectedVersion 不为 0 时必须与当前版本一致；没有任何字段变化时不产生新版本
+func (s *ArticleService) updateWithRevision(id, editorID, expectedVersion int, apply func(article *model.Article)) (*model.Article, error) {
+	article, err := s.articleRepo.GetByIDForEdit(id)
+	if err != nil {
+		return nil, fmt.Errorf("article not found")
+	}
+	if expectedVersion != 0 && expectedVersion != article.Version {
+		return nil, &VersionConflictError{Current: article}
+	}
+
+	previous := *article
+	previous.Tags = append([]string(nil), article.Tags...)
+	apply(article)
+
+	var revision *model.ArticleRevision
+	if changes := articleChanges(&previous, article); len(changes) > 0 {
+		revision = &model.ArticleRevision{
+			ArticleID:  id,
+			Title:      previous.Title,
+			Slug:       previous.Slug,
+			Content:    previous.Content,
+			Summary:    previous.Summary,
+			Tags:       previous.Tags,
+			Status:     previous.Status,
+			CoverImage: previous.CoverImage,
+			Changes:    changes,
+
+			CategoryID:   previous.CategoryID,
+			Visibility:   previous.Visibility,
+			PasswordHash: previous.PasswordHash,
+			ExpiresAt:    previous.ExpiresAt,
+			ExpiryAction: previous.ExpiryAction,
+		}
+		if editorID > 0 {
+			revision.EditorID = &editorID
+		}
+	}
+
+	err = s.articleRepo.UpdateWithRevision(article, revision)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		// 读取之后被其他请求抢先修改
+		current, getErr := s.articleRepo.GetByIDForEdit(id)
+		if getErr != nil {
+			return nil, fmt.Errorf("article not found")
+		}
+		return nil, &VersionConflictError{Current: current}
+	}
+	if err != nil {
+		s.logger.Error("Failed to update article", "articleID", id, "error", err)
+		return nil, fmt.Errorf("failed to update article")
+	}
+
+	if revision != nil {
+		s.logger.Info("Article updated", "articleID", id, "revision", revision.Revision, "changes", revision.Changes)
+	} else {
+		s.logger.Info("Article updated", "articleID", id)
+	}
+	return article, nil
+}
+
+// articleChanges 返回两个版本之间发生变化的字段名。
+// slug 为空表示将由标题重新生成，视为随标题一起变化
+func articleChanges(before, after *model.Article) []string {
+	changes := []string{}
+	if before.Title != after.Title {
+		changes = append(changes, "title")
+	}
+	if after.Slug != "" && before.Slug != after.Slug {
+		changes = append(changes, "slug")
+	}
+	if before.Content != after.Content {
+		changes = append(changes, "content")
+	}
+	if before.Summary != after.Summary {
+		changes = append(changes, "summary")
+	}
+	if strings.Join(before.Tags, "\x00") != strings.Join(after.Tags, "\x00") {
+		changes = append(changes, "tags")
+	}
+	if before.Status != after.Status {
+		changes = append(changes, "status")
+	}
+	if stringValue(before.CoverImage) != stringValue(after.CoverImage) {
+		changes = append(changes, "cover_image")
+	}
+	if !timeEqual(before.PublishedAt, after.PublishedAt) {
+		changes = append(changes, "published_at")
+	}
+	if !intEqual(before.CategoryID, after.CategoryID) {
+		changes = append(changes, "category_id")
+	}
+	if before.Visibility != after.Visibility {
+		changes = append(changes, "visibility")
+	}
+	if before.PasswordHash != after.PasswordHash {
+		changes = append(changes, "password")
+	}
+	if !timeEqual(before.ExpiresAt, after.ExpiresAt) {
+		changes = append(changes, "expires_at")
+	}
+	if before.ExpiryAction != after.ExpiryAction {
+		changes = append(changes, "expiry_action")
+	}
+	return changes
+}
+
+func (s *ArticleService) GetRevisions(articleID int) ([]model.ArticleRevision, error) {
+	if _, err := s.articleRepo.GetByIDForEdit(articleID); err != nil {
+		return nil, fmt.Errorf("article not found")
+	}
+
+	revisions, err := s.articleRepo.GetRevisions(articleID)
+	if err != nil {
+		s.logger.Error("Failed to get revisions", "articleID", articleID, "error", err)
+		return nil, fmt.Errorf("failed to get revisions")
+	}
+	return revisions, nil
+}
+
+func (s *ArticleService) GetRevision(articleID, revision int) (*model.ArticleRevision, error) {
+	result, err := s.articleRepo.GetRevision(articleID, revision)
+	if err != nil {
+		if err.Error() != "revision not found" {
+			s.logger.Error("Failed to get revision", "articleID", articleID, "revision", revision, "error", err)
+		}
+		return nil, fmt.Errorf("revision not found")
+	}
+	return result, nil
+}
+
+// DiffRevisions 比较两个版本，to 为 0 时与文章当前内容比较
+func (s *ArticleService) DiffRevisions(articleID, from, to int) (*model.RevisionDiff, error) {
+	base, err := s.GetRevision(articleID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *model.ArticleRevision
+	if to == 0 {
+		article, err := s.articleRepo.GetByIDForEdit(articleID)
+		if err != nil {
+			return nil, fmt.Errorf("article not found")
+		}
+		target = &model.ArticleRevision{
+			Title:      article.Title,
+			Slug:       article.Slug,
+			Content:    article.Content,
+			Summary:    article.Summary,
+			Tags:       article.Tags,
+			Status:     article.Status,
+			CoverImage: article.CoverImage,
+
+			CategoryID:   article.CategoryID,
+			Visibility:   article.Visibility,
+			PasswordHash: article.PasswordHash,
+			ExpiresAt:    article.ExpiresAt,
+			ExpiryAction: article.ExpiryAction,
+		}
+	} else if target, err = s.GetRevision(articleID, to); err != nil {
+		return nil, err
+	}
+
+	diff := &model.RevisionDiff{From: from, To: to, Fields: []model.FieldChange{}}
+	addField := func(field string, oldValue, newValue interface{}, changed bool) {
+		if changed {
+			diff.Fields = append(diff.Fields, model.FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	addField("title", base.Title, target.Title, base.Title != target.Title)
+	addField("slug", base.Slug, target.Slug, base.Slug != target.Slug)
+	addField("summary", base.Summary, target.Summary, base.Summary != target.Summary)
+	addField("tags", base.Tags, target.Tags, strings.Join(base.Tags, "\x00") != strings.Join(target.Tags, "\x00"))
+	addField("status", base.Status, target.Status, base.Status != target.Status)
+	addField("cover_image", base.CoverImage, target.CoverImage, stringValue(base.CoverImage) != stringValue(target.CoverImage))
+	addField("category_id", base.CategoryID, target.CategoryID, !intEqual(base.CategoryID, target.CategoryID))
+	addField("visibility", base.Visibility, target.Visibility, base.Visibility != target.Visibility)
+	// 密码只标记是否修改，不返回哈希
+	addField("password", nil, nil, base.PasswordHash != target.PasswordHash)
+	addField("expires_at", base.ExpiresAt, target.ExpiresAt, !timeEqual(base.ExpiresAt, target.ExpiresAt))
+	addField("expiry_action", base.ExpiryAction, target.ExpiryAction, base.ExpiryAction != target.ExpiryAction)
+
+	diff.Lines, diff.Added, diff.Removed = lineDiff(base.Content, target.Content)
+	return diff, nil
+}
+
+// RestoreRevision 用历史版本的标题、slug、摘要、正文、标签、封面、分类、可见性与到期设置覆盖当前内容，
+// 状态与发布时间保持不变。已删除的分类和已经过去的到期时间不恢复。恢复前的内容同样会保存为一个新版本
+func (s *ArticleService) RestoreRevision(articleID, revision, editorID int) (*model.Article, error) {
+	snapshot, err := s.GetRevision(articleID, revision)
+	if err != nil {
+		return nil, err
+	}
+	category, err := s.resolveCategory(snapshot.CategoryID)
+	if err != nil && err.Error() != "category not found" {
+		return nil, err
+	}
+	restoreExpiry := snapshot.ExpiresAt == nil || snapshot.ExpiresAt.After(time.Now())
+
+	article, err := s.updateWithRevision(articleID, editorID, 0, func(article *model.Article) {
+		article.Title = snapshot.Title
+		article.Slug = snapshot.Slug
+		article.Content = snapshot.Content
+		article.Summary = snapshot.Summary
+		// 历史版本的摘要与当时正文的自动摘要一致时，恢复后继续随正文更新
+		article.SummaryAuto = snapshot.Summary == autoSummary("", snapshot.Content)
+		article.Tags = snapshot.Tags
+		article.CoverImage = snapshot.CoverImage
+		article.CategoryID = nil
+		article.Category = category
+		if category != nil {
+			article.CategoryID = &category.ID
+		}
+		article.Visibility = snapshot.Visibility
+		article.PasswordHash = snapshot.PasswordHash
+		if restoreExpiry {
+			article.ExpiresAt = snapshot.ExpiresAt
+			article.ExpiryAction = snapshot.ExpiryAction
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Article revision restored", "articleID", articleID, "revision", revision)
+	return article, nil
+}
+
+// lineDiff 按行比较两段文本，返回逐行结果以及新增、删除的行数
+func lineDiff(before, after string) ([]model.DiffLine, int, int) {
+	// 统一结尾换行，避免最后一行仅因缺少换行符而被视为修改；空文本没有任何行
+	if before != "" && !strings.HasSuffix(before, "\n") {
+		before += "\n"
+	}
+	if after != "" && !strings.HasSuffix(after, "\n") {
+		after += "\n"
+	}
+
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lineArray := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lineArray)
+
+	lines := []model.DiffLine{}
+	oldLine, newLine, added, removed := 0, 0, 0, 0
+	for _, d := range diffs {
+		for _, text := range splitLines(d.Text) {
+			line := model.DiffLine{Text: text}
+			switch d.Type {
+			case diffmatchpatch.DiffEqual:
+				oldLine++
+				newLine++
+				line.Type, line.OldLine, line.NewLine = "equal", oldLine, newLine
+			case diffmatchpatch.DiffDelete:
+				oldLine++
+				removed++
+				line.Type, line.OldLine = "delete", oldLine
+			case diffmatchpatch.DiffInsert:
+				newLine++
+				added++
+				line.Type, line.NewLine = "insert", newLine
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines, added, removed
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func timeEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
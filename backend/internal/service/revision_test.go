@@ -0,0 +1,101 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"pea-blog-backend/internal/model"
+)
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  string
+		after   string
+		lines   []model.DiffLine
+		added   int
+		removed int
+	}{
+		{
+			name:   "trailing newline is ignored",
+			before: "a\nb",
+			after:  "a\nb\n",
+			lines: []model.DiffLine{
+				{Type: "equal", OldLine: 1, NewLine: 1, Text: "a"},
+				{Type: "equal", OldLine: 2, NewLine: 2, Text: "b"},
+			},
+		},
+		{
+			name:   "line added",
+			before: "a\nb",
+			after:  "a\nb\nc",
+			lines: []model.DiffLine{
+				{Type: "equal", OldLine: 1, NewLine: 1, Text: "a"},
+				{Type: "equal", OldLine: 2, NewLine: 2, Text: "b"},
+				{Type: "insert", NewLine: 3, Text: "c"},
+			},
+			added: 1,
+		},
+		{
+			name:   "line removed",
+			before: "a\nb\nc",
+			after:  "a\nc",
+			lines: []model.DiffLine{
+				{Type: "equal", OldLine: 1, NewLine: 1, Text: "a"},
+				{Type: "delete", OldLine: 2, Text: "b"},
+				{Type: "equal", OldLine: 3, NewLine: 2, Text: "c"},
+			},
+			removed: 1,
+		},
+		{
+			name:   "line changed",
+			before: "a\nold\nc",
+			after:  "a\nnew\nc",
+			lines: []model.DiffLine{
+				{Type: "equal", OldLine: 1, NewLine: 1, Text: "a"},
+				{Type: "delete", OldLine: 2, Text: "old"},
+				{Type: "insert", NewLine: 2, Text: "new"},
+				{Type: "equal", OldLine: 3, NewLine: 3, Text: "c"},
+			},
+			added:   1,
+			removed: 1,
+		},
+		{
+			name:   "empty before",
+			before: "",
+			after:  "a\nb",
+			lines: []model.DiffLine{
+				{Type: "insert", NewLine: 1, Text: "a"},
+				{Type: "insert", NewLine: 2, Text: "b"},
+			},
+			added: 2,
+		},
+		{
+			name:   "empty after",
+			before: "a\n",
+			after:  "",
+			lines: []model.DiffLine{
+				{Type: "delete", OldLine: 1, Text: "a"},
+			},
+			removed: 1,
+		},
+		{
+			name:   "both empty",
+			before: "",
+			after:  "",
+			lines:  []model.DiffLine{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, added, removed := lineDiff(tt.before, tt.after)
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("lines = %+v, want %+v", lines, tt.lines)
+			}
+			if added != tt.added || removed != tt.removed {
+				t.Errorf("added, removed = %d, %d, want %d, %d", added, removed, tt.added, tt.removed)
+			}
+		})
+	}
+}
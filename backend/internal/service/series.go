@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/repository"
+	"pea-blog-backend/pkg/logger"
+)
+
+type SeriesService struct {
+	seriesRepo *repository.SeriesRepository
+	logger     *logger.Logger
+}
+
+func NewSeriesService(seriesRepo *repository.SeriesRepository, logger *logger.Logger) *SeriesService {
+	return &SeriesService{
+		seriesRepo: seriesRepo,
+		logger:     logger,
+	}
+}
+
+// GetAllSeries 返回系列列表。includeUnpublished 为 false 时只列出已发布的文章，并跳过没有已发布文章的系列
+func (s *SeriesService) GetAllSeries(includeUnpublished bool) ([]model.Series, error) {
+	seriesList, err := s.seriesRepo.GetAll(!includeUnpublished)
+	if err != nil {
+		s.logger.Error("Failed to get series", "error", err)
+		return nil, fmt.Errorf("failed to get series")
+	}
+	if includeUnpublished {
+		return seriesList, nil
+	}
+
+	visible := []model.Series{}
+	for _, series := range seriesList {
+		if len(series.Articles) > 0 {
+			visible = append(visible, series)
+		}
+	}
+	return visible, nil
+}
+
+func (s *SeriesService) GetSeries(id int, includeUnpublished bool) (*model.Series, error) {
+	series, err := s.seriesRepo.GetByID(id, !includeUnpublished)
+	if err != nil {
+		if err.Error() == "series not found" {
+			return nil, err
+		}
+		s.logger.Error("Failed to get series", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get series")
+	}
+	if !includeUnpublished && len(series.Articles) == 0 {
+		return nil, fmt.Errorf("series not found")
+	}
+	return series, nil
+}
+
+func (s *SeriesService) CreateSeries(req *model.SeriesRequest) (*model.Series, error) {
+	series, articleIDs, err := s.prepare(0, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepo.Create(series, articleIDs); err != nil {
+		if err.Error() == "article not found" {
+			return nil, err
+		}
+		s.logger.Error("Failed to create series", "title", series.Title, "error", err)
+		return nil, fmt.Errorf("failed to create series")
+	}
+
+	s.logger.Info("Series created", "id", series.ID, "articles", len(articleIDs))
+	return s.GetSeries(series.ID, true)
+}
+
+// UpdateSeries 修改系列信息，ArticleIDs 为系列中文章的完整新顺序
+func (s *SeriesService) UpdateSeries(id int, req *model.SeriesRequest) (*model.Series, error) {
+	series, articleIDs, err := s.prepare(id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepo.Update(series, articleIDs); err != nil {
+		if err.Error() == "series not found" || err.Error() == "article not found" {
+			return nil, err
+		}
+		s.logger.Error("Failed to update series", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to update series")
+	}
+
+	s.logger.Info("Series updated", "id", id, "articles", len(articleIDs))
+	return s.GetSeries(id, true)
+}
+
+func (s *SeriesService) DeleteSeries(id int) error {
+	if err := s.seriesRepo.Delete(id); err != nil {
+		if err.Error() == "series not found" {
+			return err
+		}
+		s.logger.Error("Failed to delete series", "id", id, "error", err)
+		return fmt.Errorf("failed to delete series")
+	}
+
+	s.logger.Info("Series deleted", "id", id)
+	return nil
+}
+
+// prepare 校验标题是否重复，并去掉重复的文章 ID
+func (s *SeriesService) prepare(id int, req *model.SeriesRequest) (*model.Series, []int, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, nil, fmt.Errorf("series title is required")
+	}
+
+	existingID, err := s.seriesRepo.GetIDByTitle(title)
+	if err != nil {
+		s.logger.Error("Failed to check series title", "title", title, "error", err)
+		return nil, nil, fmt.Errorf("failed to check series title")
+	}
+	if existingID != 0 && existingID != id {
+		return nil, nil, fmt.Errorf("series already exists")
+	}
+
+	articleIDs := make([]int, 0, len(req.ArticleIDs))
+	seen := map[int]bool{}
+	for _, articleID := range req.ArticleIDs {
+		if !seen[articleID] {
+			seen[articleID] = true
+			articleIDs = append(articleIDs, articleID)
+		}
+	}
+
+	return &model.Series{
+		ID:          id,
+		Title:       title,
+		Description: strings.TrimSpace(req.Description),
+	}, articleIDs, nil
+}
+
+// attachSeries 填充文章详情中的系列信息：位置及上一篇、下一篇。
+// includeUnpublished 为 false 时只在已发布的文章之间导航；查询失败只记录日志
+func (s *ArticleService) attachSeries(article *model.Article, includeUnpublished bool) {
+	series, err := s.seriesRepo.GetByArticleID(article.ID, !includeUnpublished)
+	if err != nil {
+		s.logger.Error("Failed to get article series", "articleID", article.ID, "error", err)
+		return
+	}
+	if series == nil {
+		return
+	}
+
+	for i, part := range series.Articles {
+		if part.ID != article.ID {
+			continue
+		}
+		article.Series = &model.ArticleSeries{
+			ID:       series.ID,
+			Title:    series.Title,
+			Position: part.Position,
+			Total:    len(series.Articles),
+		}
+		if i > 0 {
+			article.Series.Previous = &series.Articles[i-1]
+		}
+		if i < len(series.Articles)-1 {
+			article.Series.Next = &series.Articles[i+1]
+		}
+		return
+	}
+}
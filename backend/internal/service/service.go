@@ -1,26 +1,39 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"pea-blog-backend/internal/model"
 	"pea-blog-backend/internal/repository"
+	"pea-blog-backend/internal/storage"
 	"pea-blog-backend/internal/util"
 	"pea-blog-backend/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type AuthService struct {
-	userRepo *repository.UserRepository
-	logger   *logger.Logger
+	userRepo         *repository.UserRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+	sessionRepo      *repository.SessionRepository
+	logger           *logger.Logger
 }
 
-func NewAuthService(userRepo *repository.UserRepository, logger *logger.Logger) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, refreshTokenRepo *repository.RefreshTokenRepository, sessionRepo *repository.SessionRepository, logger *logger.Logger) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		logger:   logger,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		logger:           logger,
 	}
 }
 
-func (s *AuthService) Login(req model.LoginRequest) (*model.LoginResponse, error) {
+// 会话最后活跃时间的最小更新间隔，避免每个请求都写库
+const sessionTouchInterval = time.Minute
+
+func (s *AuthService) Login(req model.LoginRequest, ipAddress, userAgent string) (*model.LoginResponse, error) {
 	user, err := s.userRepo.GetByUsername(req.Username)
 	if err != nil {
 		s.logger.Error("Failed to get user by username", "username", req.Username, "error", err)
@@ -32,7 +45,195 @@ func (s *AuthService) Login(req model.LoginRequest) (*model.LoginResponse, error
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
-	token, err := util.GenerateJWT(user.ID, user.Username, user.Role)
+	refreshToken, hash, expiresAt, err := util.GenerateRefreshToken()
+	if err != nil {
+		s.logger.Error("Failed to generate refresh token", "userID", user.ID, "error", err)
+		return nil, fmt.Errorf("failed to generate token")
+	}
+
+	session := &model.Session{
+		ID:         uuid.New().String(),
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		LastSeenAt: time.Now(),
+		ExpiresAt:  expiresAt,
+	}
+	// 先签发 access token，签发失败时不留下无人使用的会话
+	resp, err := s.buildLoginResponse(user, session.ID, refreshToken, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		s.logger.Error("Failed to create session", "userID", user.ID, "error", err)
+		return nil, fmt.Errorf("failed to generate token")
+	}
+
+	err = s.refreshTokenRepo.Create(&model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hash,
+		FamilyID:  session.ID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		s.logger.Error("Failed to store refresh token", "userID", user.ID, "error", err)
+		if err := s.sessionRepo.Revoke(session.ID); err != nil {
+			s.logger.Error("Failed to revoke session", "sessionID", session.ID, "error", err)
+		}
+		return nil, fmt.Errorf("failed to generate token")
+	}
+
+	return resp, nil
+}
+
+// RefreshToken 用 refresh token 换取新的 access token，并轮换 refresh token。
+// 已被使用过的 refresh token 再次出现时视为泄露，整个会话（token 家族）都会被吊销。
+func (s *AuthService) RefreshToken(req model.RefreshTokenRequest, ipAddress string) (*model.LoginResponse, error) {
+	current, err := s.refreshTokenRepo.GetByHash(util.HashToken(req.RefreshToken))
+	if err != nil {
+		s.logger.Warn("Unknown refresh token presented", "error", err)
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if current.RevokedAt != nil {
+		s.revokeReusedFamily(current)
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, fmt.Errorf("refresh token expired")
+	}
+
+	session, err := s.sessionRepo.GetByID(current.FamilyID)
+	if err != nil || session.RevokedAt != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	user, err := s.userRepo.GetByID(current.UserID)
+	if err != nil {
+		s.logger.Error("Failed to get user for refresh token", "userID", current.UserID, "error", err)
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	refreshToken, hash, expiresAt, err := util.GenerateRefreshToken()
+	if err != nil {
+		s.logger.Error("Failed to generate refresh token", "userID", user.ID, "error", err)
+		return nil, fmt.Errorf("failed to generate token")
+	}
+	next := &model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hash,
+		FamilyID:  current.FamilyID,
+		ExpiresAt: expiresAt,
+	}
+	// 先签发 access token，签发失败时旧的 refresh token 仍然有效
+	resp, err := s.buildLoginResponse(user, session.ID, refreshToken, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshTokenRepo.Rotate(current, next); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenReused) {
+			s.revokeReusedFamily(current)
+			return nil, fmt.Errorf("invalid refresh token")
+		}
+		s.logger.Error("Failed to rotate refresh token", "userID", user.ID, "error", err)
+		return nil, fmt.Errorf("failed to refresh token")
+	}
+
+	if err := s.sessionRepo.Extend(session.ID, ipAddress, expiresAt); err != nil {
+		s.logger.Error("Failed to extend session", "sessionID", session.ID, "error", err)
+	}
+
+	return resp, nil
+}
+
+// Logout 吊销当前会话及其 refresh token 家族
+func (s *AuthService) Logout(userID int, sessionID string) error {
+	return s.RevokeSession(userID, sessionID)
+}
+
+// ValidateSession 校验 access token 对应的会话仍然有效，供认证中间件调用
+func (s *AuthService) ValidateSession(userID int, sessionID string) error {
+	if sessionID == "" {
+		return fmt.Errorf("session not found")
+	}
+
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return fmt.Errorf("session not found")
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return fmt.Errorf("session revoked")
+	}
+
+	now := time.Now()
+	if now.After(session.ExpiresAt) {
+		return fmt.Errorf("session expired")
+	}
+
+	if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		if err := s.sessionRepo.Touch(session.ID, now); err != nil {
+			s.logger.Error("Failed to update session last seen time", "sessionID", session.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *AuthService) GetSessions(userID int, currentSessionID string) ([]model.Session, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(userID)
+	if err != nil {
+		s.logger.Error("Failed to get sessions", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to get sessions")
+	}
+
+	now := time.Now()
+	active := []model.Session{}
+	for _, session := range sessions {
+		if now.After(session.ExpiresAt) {
+			continue
+		}
+		session.Device = util.DescribeUserAgent(session.UserAgent)
+		session.Current = session.ID == currentSessionID
+		active = append(active, session)
+	}
+
+	return active, nil
+}
+
+func (s *AuthService) RevokeSession(userID int, sessionID string) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return fmt.Errorf("session not found")
+	}
+
+	if err := s.sessionRepo.Revoke(session.ID); err != nil {
+		s.logger.Error("Failed to revoke session", "sessionID", session.ID, "error", err)
+		return fmt.Errorf("failed to revoke session")
+	}
+
+	s.logger.Info("Session revoked", "userID", userID, "sessionID", session.ID)
+	return nil
+}
+
+func (s *AuthService) RevokeAllSessions(userID int) (int, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return 0, fmt.Errorf("user not found")
+	}
+
+	revoked, err := s.sessionRepo.RevokeAllByUserID(userID)
+	if err != nil {
+		s.logger.Error("Failed to revoke user sessions", "userID", userID, "error", err)
+		return 0, fmt.Errorf("failed to revoke sessions")
+	}
+
+	s.logger.Info("All sessions revoked", "userID", userID, "count", revoked)
+	return revoked, nil
+}
+
+func (s *AuthService) buildLoginResponse(user *model.User, sessionID, refreshToken string, refreshExpiresAt time.Time) (*model.LoginResponse, error) {
+	token, err := util.GenerateJWT(user.ID, user.Username, user.Role, sessionID)
 	if err != nil {
 		s.logger.Error("Failed to generate JWT", "userID", user.ID, "error", err)
 		return nil, fmt.Errorf("failed to generate token")
@@ -41,11 +242,20 @@ func (s *AuthService) Login(req model.LoginRequest) (*model.LoginResponse, error
 	user.Password = ""
 
 	return &model.LoginResponse{
-		Token: token,
-		User:  *user,
+		Token:                 token,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+		User:                  *user,
 	}, nil
 }
 
+func (s *AuthService) revokeReusedFamily(token *model.RefreshToken) {
+	s.logger.Warn("Refresh token reuse detected, revoking session", "userID", token.UserID, "sessionID", token.FamilyID)
+	if err := s.sessionRepo.Revoke(token.FamilyID); err != nil {
+		s.logger.Error("Failed to revoke session", "sessionID", token.FamilyID, "error", err)
+	}
+}
+
 func (s *AuthService) GetCurrentUser(userID int) (*model.User, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
@@ -60,14 +270,20 @@ func (s *AuthService) GetCurrentUser(userID int) (*model.User, error) {
 type ArticleService struct {
 	articleRepo *repository.ArticleRepository
 	userRepo    *repository.UserRepository
-	logger      *logger.Logger
+	commentRepo *repository.CommentRepository
+	seriesRepo   *repository.SeriesRepository
+	categoryRepo *repository.CategoryRepository
+	logger       *logger.Logger
 }
 
-func NewArticleService(articleRepo *repository.ArticleRepository, userRepo *repository.UserRepository, logger *logger.Logger) *ArticleService {
+func NewArticleService(articleRepo *repository.ArticleRepository, userRepo *repository.UserRepository, commentRepo *repository.CommentRepository, seriesRepo *repository.SeriesRepository, categoryRepo *repository.CategoryRepository, logger *logger.Logger) *ArticleService {
 	return &ArticleService{
-		articleRepo: articleRepo,
-		userRepo:    userRepo,
-		logger:      logger,
+		articleRepo:  articleRepo,
+		userRepo:     userRepo,
+		commentRepo:  commentRepo,
+		seriesRepo:   seriesRepo,
+		categoryRepo: categoryRepo,
+		logger:       logger,
 	}
 }
 
@@ -85,12 +301,14 @@ func (s *ArticleService) GetArticles(params model.SearchParams) (*model.ArticleL
 		params.SortOrder = "desc"
 	}
 
-	params.IncludeDrafts = true
 	articles, total, err := s.articleRepo.GetAll(params)
 	if err != nil {
 		s.logger.Error("Failed to get articles from repository", "error", err, "params", params)
 		return nil, fmt.Errorf("failed to get articles from repository: %w", err)
 	}
+	if !params.IncludeDrafts {
+		lockProtectedArticles(articles)
+	}
 
 	return &model.ArticleListResponse{
 		Articles: articles,
@@ -113,54 +331,189 @@ func (s *ArticleService) GetPublishedArticles(params model.SearchParams) (*model
 	if params.SortOrder == "" {
 		params.SortOrder = "desc"
 	}
+	// 只返回公开列出的已发布文章
+	params.IncludeDrafts = false
 
 	articles, total, err := s.articleRepo.GetAll(params)
 	if err != nil {
 		s.logger.Error("Failed to get published articles from repository", "error", err, "params", params)
 		return nil, fmt.Errorf("failed to get published articles from repository: %w", err)
 	}
+	lockProtectedArticles(articles)
+
+	return &model.ArticleListResponse{
+		Articles: articles,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}, nil
+}
+
+// 搜索结果正文片段的长度（字符数）
+const searchSnippetLength = 160
+
+// SearchArticles 全文搜索文章，并为每篇文章生成高亮的标题和正文片段
+func (s *ArticleService) SearchArticles(params model.SearchParams) (*model.ArticleListResponse, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > 100 {
+		params.PageSize = 10
+	}
+	if params.SortBy == "" {
+		params.SortBy = "relevance"
+	}
+	if params.SortOrder == "" {
+		params.SortOrder = "desc"
+	}
+
+	articles, total, err := s.articleRepo.GetAll(params)
+	if err != nil {
+		s.logger.Error("Failed to search articles", "error", err, "params", params)
+		return nil, fmt.Errorf("failed to search articles: %w", err)
+	}
+	if !params.IncludeDrafts {
+		// 受密码保护的文章只用摘要生成片段
+		lockProtectedArticles(articles)
+	}
+
+	terms := util.SplitSearchTerms(params.Keyword)
+	for i := range articles {
+		articles[i].Highlight = buildHighlight(&articles[i], terms)
+	}
+
+	facets, err := s.articleRepo.GetFacets(params)
+	if err != nil {
+		s.logger.Error("Failed to get search facets", "error", err, "params", params)
+		return nil, fmt.Errorf("failed to get search facets: %w", err)
+	}
 
 	return &model.ArticleListResponse{
 		Articles: articles,
 		Total:    total,
 		Page:     params.Page,
 		PageSize: params.PageSize,
+		Facets:   facets,
 	}, nil
 }
 
-func (s *ArticleService) GetArticleByID(id int) (*model.Article, error) {
+func buildHighlight(article *model.Article, terms []string) *model.ArticleHighlight {
+	snippet := ""
+	if !article.Locked {
+		// 未解锁的文章不从正文生成片段
+		snippet = util.Snippet(util.MarkdownToText(article.Content), terms, searchSnippetLength)
+	}
+	if snippet == "" {
+		snippet = util.Snippet(article.Summary, terms, searchSnippetLength)
+	}
+	if snippet == "" {
+		// 只有标题命中时，用摘要开头作为片段
+		summary := []rune(article.Summary)
+		if len(summary) > searchSnippetLength {
+			summary = append(summary[:searchSnippetLength], '…')
+		}
+		snippet = util.Highlight(string(summary), terms)
+	}
+	return &model.ArticleHighlight{
+		Title:   util.Highlight(article.Title, terms),
+		Snippet: snippet,
+	}
+}
+
+// GetArticleByID 获取文章详情。includeUnpublished 为 false 时草稿、定时和私密文章视为不存在，
+// 受密码保护的文章只有 accessToken 有效时才返回正文
+func (s *ArticleService) GetArticleByID(id int, includeUnpublished bool, accessToken string) (*model.Article, error) {
 	article, err := s.articleRepo.GetByID(id)
 	if err != nil {
 		s.logger.Error("Failed to get article by ID", "articleID", id, "error", err)
 		return nil, fmt.Errorf("article not found")
 	}
+	if err := s.checkAccess(article, includeUnpublished, accessToken); err != nil {
+		return nil, err
+	}
+	s.attachSeries(article, includeUnpublished)
 
 	return article, nil
 }
 
-func (s *ArticleService) GetArticleByTitle(title string) (*model.Article, error) {
+func (s *ArticleService) GetArticleByTitle(title string, includeUnpublished bool, accessToken string) (*model.Article, error) {
 	article, err := s.articleRepo.GetByTitle(title)
 	if err != nil {
 		s.logger.Error("Failed to get article by title", "title", title, "error", err)
 		return nil, fmt.Errorf("article not found")
 	}
+	if err := s.checkAccess(article, includeUnpublished, accessToken); err != nil {
+		return nil, err
+	}
+	s.attachSeries(article, includeUnpublished)
 
 	return article, nil
 }
 
+// GetArticleBySlug 按 slug 获取文章。slug 已变更时返回 nil 和文章的当前 slug
+func (s *ArticleService) GetArticleBySlug(slug string, includeUnpublished bool, accessToken string) (*model.Article, string, error) {
+	article, err := s.articleRepo.GetBySlug(slug)
+	if err == nil {
+		if err := s.checkAccess(article, includeUnpublished, accessToken); err != nil {
+			return nil, "", err
+		}
+		s.attachSeries(article, includeUnpublished)
+		return article, "", nil
+	}
+	if err.Error() != "article not found" {
+		s.logger.Error("Failed to get article by slug", "slug", slug, "error", err)
+		return nil, "", fmt.Errorf("article not found")
+	}
+
+	id, current, err := s.articleRepo.GetCurrentSlug(slug)
+	if err != nil {
+		s.logger.Error("Failed to look up slug history", "slug", slug, "error", err)
+	}
+	if current == "" {
+		return nil, "", fmt.Errorf("article not found")
+	}
+	// 只对读者可以查看的文章重定向，避免通过旧 slug 探测草稿和私密文章的当前 slug
+	target, err := s.articleRepo.GetByIDForEdit(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("article not found")
+	}
+	if err := s.checkAccess(target, includeUnpublished, accessToken); err != nil {
+		return nil, "", err
+	}
+	return nil, current, nil
+}
+
 func (s *ArticleService) CreateArticle(req model.CreateArticleRequest, authorID int) (*model.Article, error) {
 	article := &model.Article{
 		Title:      req.Title,
+		Slug:       req.Slug,
 		Content:    req.Content,
-		Summary:    req.Summary,
 		Tags:       req.Tags,
 		AuthorID:   authorID,
 		Status:     req.Status,
 		CoverImage: req.CoverImage,
 		PublishedAt: req.PublishedAt,
 	}
+	setSummary(article, req.Summary)
+
+	category, err := s.resolveCategory(req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category != nil {
+		article.CategoryID = &category.ID
+		article.Category = category
+	}
+	if article.Visibility, article.PasswordHash, err = articleVisibility(req.Visibility, req.Password, ""); err != nil {
+		return nil, err
+	}
+	if err := validateExpiry(req.PublishedAt, req.ExpiresAt); err != nil {
+		return nil, err
+	}
+	article.ExpiresAt = utcTime(req.ExpiresAt)
+	article.ExpiryAction = req.ExpiryAction
 
-	err := s.articleRepo.Create(article)
+	err = s.articleRepo.Create(article)
 	if err != nil {
 		s.logger.Error("Failed to create article", "authorID", authorID, "error", err)
 		return nil, fmt.Errorf("failed to create article")
@@ -176,52 +529,87 @@ func (s *ArticleService) CreateArticle(req model.CreateArticleRequest, authorID
 	return article, nil
 }
 
-func (s *ArticleService) UpdateArticle(id int, req model.UpdateArticleRequest) (*model.Article, error) {
-	article, err := s.articleRepo.GetByID(id)
-	if err != nil {
-		return nil, fmt.Errorf("article not found")
+// UpdateArticle 更新文章，更新前的内容保存为一个历史版本。
+// req.Version 与当前版本不一致时返回 *VersionConflictError
+func (s *ArticleService) UpdateArticle(id int, req model.UpdateArticleRequest, editorID int) (*model.Article, error) {
+	if req.Version == nil {
+		return nil, fmt.Errorf("version is required")
 	}
-
-	if req.Title != nil {
-		article.Title = *req.Title
-	}
-	if req.Content != nil {
-		article.Content = *req.Content
-	}
-	if req.Summary != nil {
-		article.Summary = *req.Summary
-	}
-	if req.Tags != nil {
-		article.Tags = req.Tags
-	}
-	if req.Status != nil {
-		article.Status = *req.Status
-	}
-	if req.CoverImage != nil {
-		article.CoverImage = req.CoverImage
-	}
-	if req.PublishedAt != nil {
-		article.PublishedAt = req.PublishedAt
+	category, err := s.resolveCategory(req.CategoryID)
+	if err != nil {
+		return nil, err
 	}
-
-	err = s.articleRepo.Update(article)
+	visibility, passwordHash, err := s.updatedVisibility(id, req)
 	if err != nil {
-		s.logger.Error("Failed to update article", "articleID", id, "error", err)
-		return nil, fmt.Errorf("failed to update article")
+		return nil, err
 	}
-
-	s.logger.Info("Article updated", "articleID", id)
-	return article, nil
+	if err := s.checkUpdatedExpiry(id, req); err != nil {
+		return nil, err
+	}
+	return s.updateWithRevision(id, editorID, *req.Version, func(article *model.Article) {
+		if req.Slug != nil {
+			article.Slug = *req.Slug
+		} else if req.Title != nil && *req.Title != article.Title {
+			// 标题变化时重新生成 slug
+			article.Slug = ""
+		}
+		if req.Title != nil {
+			article.Title = *req.Title
+		}
+		if req.Content != nil {
+			article.Content = *req.Content
+		}
+		if req.Summary != nil {
+			setSummary(article, *req.Summary)
+		} else {
+			refreshSummary(article)
+		}
+		if req.Tags != nil {
+			article.Tags = req.Tags
+		}
+		if req.Status != nil {
+			article.Status = *req.Status
+		}
+		if req.CoverImage != nil {
+			article.CoverImage = req.CoverImage
+		}
+		if req.PublishedAt != nil {
+			article.PublishedAt = req.PublishedAt
+		}
+		if req.CategoryID != nil {
+			article.CategoryID = nil
+			article.Category = category
+			if category != nil {
+				article.CategoryID = &category.ID
+			}
+		}
+		if req.Visibility != nil || req.Password != nil {
+			article.Visibility = visibility
+			article.PasswordHash = passwordHash
+		}
+		if req.ClearExpiresAt {
+			article.ExpiresAt = nil
+		} else if req.ExpiresAt != nil {
+			article.ExpiresAt = utcTime(req.ExpiresAt)
+		}
+		if req.ExpiryAction != nil {
+			article.ExpiryAction = *req.ExpiryAction
+		}
+	})
 }
 
+// DeleteArticle 把文章移入回收站
 func (s *ArticleService) DeleteArticle(id int) error {
 	err := s.articleRepo.Delete(id)
 	if err != nil {
+		if err.Error() == "article not found" {
+			return err
+		}
 		s.logger.Error("Failed to delete article", "articleID", id, "error", err)
 		return fmt.Errorf("failed to delete article")
 	}
 
-	s.logger.Info("Article deleted", "articleID", id)
+	s.logger.Info("Article moved to trash", "articleID", id)
 	return nil
 }
 
@@ -236,9 +624,17 @@ func (s *ArticleService) UnpublishArticle(id int) error {
 	return nil
 }
 
-func (s *ArticleService) LikeArticle(userID, articleID int) error {
+// LikeArticle 点赞文章，读者只能点赞可以查看的文章
+func (s *ArticleService) LikeArticle(userID, articleID int, accessToken string) error {
+	if err := checkArticleAccess(s.articleRepo, articleID, false, accessToken); err != nil {
+		return err
+	}
+
 	err := s.articleRepo.Like(userID, articleID)
 	if err != nil {
+		if err.Error() == "article not found" {
+			return err
+		}
 		s.logger.Error("Failed to like article", "userID", userID, "articleID", articleID, "error", err)
 		return fmt.Errorf("failed to like article")
 	}
@@ -246,7 +642,11 @@ func (s *ArticleService) LikeArticle(userID, articleID int) error {
 	return nil
 }
 
-func (s *ArticleService) UnlikeArticle(userID, articleID int) error {
+func (s *ArticleService) UnlikeArticle(userID, articleID int, accessToken string) error {
+	if err := checkArticleAccess(s.articleRepo, articleID, false, accessToken); err != nil {
+		return err
+	}
+
 	err := s.articleRepo.Unlike(userID, articleID)
 	if err != nil {
 		s.logger.Error("Failed to unlike article", "userID", userID, "articleID", articleID, "error", err)
@@ -278,34 +678,42 @@ func (s *ArticleService) PublishScheduledArticles() []error {
 }
 
 func (s *ArticleService) GetAllArticlesForExport() ([]model.Article, error) {
-	articles, _, err := s.articleRepo.GetAll(model.SearchParams{IncludeDrafts: true, PageSize: 9999})
-	return articles, err
-}
+	articles, err := s.articleRepo.GetAllForExport()
+	if err != nil {
+		s.logger.Error("Failed to get articles for export", "error", err)
+		return nil, fmt.Errorf("failed to get articles for export")
+	}
 
-func (s *ArticleService) ImportArticles(articles []model.Article) error {
-	for _, article := range articles {
-		if err := s.articleRepo.Create(&article); err != nil {
-			return err
+	for i := range articles {
+		if articles[i].Author != nil {
+			articles[i].Author.Password = ""
 		}
 	}
-	return nil
+	return articles, nil
 }
 
 type CommentService struct {
 	commentRepo *repository.CommentRepository
+	articleRepo *repository.ArticleRepository
 	userRepo    *repository.UserRepository
 	logger      *logger.Logger
 }
 
-func NewCommentService(commentRepo *repository.CommentRepository, userRepo *repository.UserRepository, logger *logger.Logger) *CommentService {
+func NewCommentService(commentRepo *repository.CommentRepository, articleRepo *repository.ArticleRepository, userRepo *repository.UserRepository, logger *logger.Logger) *CommentService {
 	return &CommentService{
 		commentRepo: commentRepo,
+		articleRepo: articleRepo,
 		userRepo:    userRepo,
 		logger:      logger,
 	}
 }
 
-func (s *CommentService) GetCommentsByArticleID(articleID int, page int, pageSize int) (*model.CommentListResponse, error) {
+// GetCommentsByArticleID 获取文章的评论，includeUnpublished 与 accessToken 的含义同 GetArticleByID
+func (s *CommentService) GetCommentsByArticleID(articleID int, page int, pageSize int, includeUnpublished bool, accessToken string) (*model.CommentListResponse, error) {
+	if err := checkArticleAccess(s.articleRepo, articleID, includeUnpublished, accessToken); err != nil {
+		return nil, err
+	}
+
 	comments, total, err := s.commentRepo.GetByArticleID(articleID, page, pageSize)
 	if err != nil {
 		s.logger.Error("Failed to get comments", "articleID", articleID, "error", err)
@@ -320,7 +728,15 @@ func (s *CommentService) GetCommentsByArticleID(articleID int, page int, pageSiz
 	}, nil
 }
 
-func (s *CommentService) GetRepliesByCommentID(commentID int, page int, pageSize int) (*model.CommentListResponse, error) {
+func (s *CommentService) GetRepliesByCommentID(commentID int, page int, pageSize int, includeUnpublished bool, accessToken string) (*model.CommentListResponse, error) {
+	comment, err := s.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment not found")
+	}
+	if err := checkArticleAccess(s.articleRepo, comment.ArticleID, includeUnpublished, accessToken); err != nil {
+		return nil, err
+	}
+
 	replies, total, err := s.commentRepo.GetRepliesByCommentID(commentID, page, pageSize)
 	if err != nil {
 		s.logger.Error("Failed to get replies", "commentID", commentID, "error", err)
@@ -335,7 +751,12 @@ func (s *CommentService) GetRepliesByCommentID(commentID int, page int, pageSize
 	}, nil
 }
 
-func (s *CommentService) CreateComment(req model.CreateCommentRequest, authorID int, fingerprint *string) (*model.Comment, error) {
+// CreateComment 发表评论，读者只能评论可以查看的文章
+func (s *CommentService) CreateComment(req model.CreateCommentRequest, authorID int, fingerprint *string, accessToken string) (*model.Comment, error) {
+	if err := checkArticleAccess(s.articleRepo, req.ArticleID, false, accessToken); err != nil {
+		return nil, err
+	}
+
 	if authorID == 0 && fingerprint != nil {
 		// Handle guest user
 		user, err := s.userRepo.GetByFingerprint(*fingerprint)
@@ -361,6 +782,9 @@ func (s *CommentService) CreateComment(req model.CreateCommentRequest, authorID
 
 	err := s.commentRepo.Create(comment)
 	if err != nil {
+		if err.Error() == "article not found" {
+			return nil, err
+		}
 		s.logger.Error("Failed to create comment", "authorID", authorID, "articleID", req.ArticleID, "error", err)
 		return nil, fmt.Errorf("failed to create comment")
 	}
@@ -404,16 +828,134 @@ func (s *CommentService) DeleteComment(id, userID int, isAdmin bool, fingerprint
 	return fmt.Errorf("user not authorized to delete this comment")
 }
 
+type TagService struct {
+	tagRepo *repository.TagRepository
+	logger  *logger.Logger
+}
+
+func NewTagService(tagRepo *repository.TagRepository, logger *logger.Logger) *TagService {
+	return &TagService{
+		tagRepo: tagRepo,
+		logger:  logger,
+	}
+}
+
+func (s *TagService) GetTags() ([]model.TagCount, error) {
+	tags, err := s.tagRepo.GetPublishedCounts()
+	if err != nil {
+		s.logger.Error("Failed to get tags", "error", err)
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetAllTags 返回全部标签（含未使用的）及其文章数，供后台管理
+func (s *TagService) GetAllTags() ([]model.Tag, error) {
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to get all tags", "error", err)
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// RenameTag 重命名标签，所有文章随之更新；新名称已被其他标签使用时应改用合并
+func (s *TagService) RenameTag(id int, req *model.RenameTagRequest) (*model.Tag, error) {
+	tag, err := s.tagRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("tag name is required")
+	}
+	if name == tag.Name {
+		return tag, nil
+	}
+
+	existingID, err := s.tagRepo.GetIDByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existingID != 0 && existingID != id {
+		return nil, fmt.Errorf("tag already exists")
+	}
+
+	if err := s.tagRepo.Rename(id, name); err != nil {
+		s.logger.Error("Failed to rename tag", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to rename tag: %w", err)
+	}
+	s.logger.Info("Tag renamed", "id", id, "from", tag.Name, "to", name)
+
+	tag.Name = name
+	return tag, nil
+}
+
+// MergeTags 把源标签合并到目标标签，返回新增目标标签的文章数
+func (s *TagService) MergeTags(req *model.MergeTagsRequest) (int, error) {
+	if _, err := s.tagRepo.GetByID(req.TargetID); err != nil {
+		return 0, err
+	}
+
+	var sourceIDs []int
+	seen := map[int]bool{req.TargetID: true}
+	for _, id := range req.SourceIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := s.tagRepo.GetByID(id); err != nil {
+			return 0, err
+		}
+		sourceIDs = append(sourceIDs, id)
+	}
+	if len(sourceIDs) == 0 {
+		return 0, fmt.Errorf("no source tags to merge")
+	}
+
+	affected, err := s.tagRepo.Merge(sourceIDs, req.TargetID)
+	if err != nil {
+		s.logger.Error("Failed to merge tags", "sources", sourceIDs, "target", req.TargetID, "error", err)
+		return 0, fmt.Errorf("failed to merge tags: %w", err)
+	}
+	s.logger.Info("Tags merged", "sources", sourceIDs, "target", req.TargetID, "articles", affected)
+	return affected, nil
+}
+
+// DeleteTag 删除标签并从所有文章上移除，返回受影响的文章数
+func (s *TagService) DeleteTag(id int) (int, error) {
+	if _, err := s.tagRepo.GetByID(id); err != nil {
+		return 0, err
+	}
+
+	affected, err := s.tagRepo.Delete(id)
+	if err != nil {
+		s.logger.Error("Failed to delete tag", "id", id, "error", err)
+		return 0, fmt.Errorf("failed to delete tag: %w", err)
+	}
+	s.logger.Info("Tag deleted", "id", id, "articles", affected)
+	return affected, nil
+}
+
 type Service struct {
-	Auth    *AuthService
-	Article *ArticleService
-	Comment *CommentService
+	Auth     *AuthService
+	Article  *ArticleService
+	Comment  *CommentService
+	Media    *MediaService
+	Tag      *TagService
+	Series   *SeriesService
+	Category *CategoryService
 }
 
-func New(repos *repository.Repository, logger *logger.Logger) *Service {
+func New(repos *repository.Repository, store storage.Storage, logger *logger.Logger) *Service {
 	return &Service{
-		Auth:    NewAuthService(repos.User, logger),
-		Article: NewArticleService(repos.Article, repos.User, logger),
-		Comment: NewCommentService(repos.Comment, repos.User, logger),
+		Auth:     NewAuthService(repos.User, repos.RefreshToken, repos.Session, logger),
+		Article:  NewArticleService(repos.Article, repos.User, repos.Comment, repos.Series, repos.Category, logger),
+		Comment:  NewCommentService(repos.Comment, repos.Article, repos.User, logger),
+		Media:    NewMediaService(repos.Media, store, logger),
+		Tag:      NewTagService(repos.Tag, logger),
+		Series:   NewSeriesService(repos.Series, logger),
+		Category: NewCategoryService(repos.Category, logger),
 	}
 }
\ No newline at end of file
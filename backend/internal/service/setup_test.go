@@ -0,0 +1,34 @@
+package service
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"pea-blog-backend/internal/repository"
+	"pea-blog-backend/pkg/database"
+	"pea-blog-backend/pkg/logger"
+)
+
+// newTestService 在临时 SQLite 数据库上构造服务，迁移时创建的管理员 ID 为 1
+func newTestService(t *testing.T) *Service {
+	s, _ := newTestServiceDB(t)
+	return s
+}
+
+// newTestServiceDB 同 newTestService，另外返回数据库连接，供测试直接准备数据
+func newTestServiceDB(t *testing.T) (*Service, *sql.DB) {
+	t.Helper()
+	db, err := database.Connect(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := repository.Backfill(db); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	return New(repository.New(db), nil, logger.New("test")), db
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"strings"
+
+	"pea-blog-backend/internal/markdown"
+	"pea-blog-backend/internal/model"
+)
+
+// AutoSummaryLength 为自动生成的摘要的最大字符数
+const AutoSummaryLength = 150
+
+// autoSummary 在摘要为空时由渲染后的正文段落生成摘要，否则原样返回
+func autoSummary(summary, content string) string {
+	if strings.TrimSpace(summary) != "" {
+		return summary
+	}
+	rendered, _, err := markdown.Render(content)
+	if err != nil {
+		return ""
+	}
+	return markdown.Summary(rendered, AutoSummaryLength)
+}
+
+// setSummary 设置文章摘要，summary 为空时由正文生成并标记为自动摘要
+func setSummary(article *model.Article, summary string) {
+	article.SummaryAuto = strings.TrimSpace(summary) == ""
+	article.Summary = autoSummary(summary, article.Content)
+}
+
+// refreshSummary 在正文修改后重新生成自动摘要，手写的摘要保持不变
+func refreshSummary(article *model.Article) {
+	if article.SummaryAuto {
+		article.Summary = autoSummary("", article.Content)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"pea-blog-backend/internal/model"
+)
+
+func TestMergeTags(t *testing.T) {
+	s := newTestService(t)
+	create := func(title string, tags ...string) int {
+		t.Helper()
+		article, err := s.Article.CreateArticle(model.CreateArticleRequest{
+			Title: title, Content: "content", Status: "published", Tags: tags,
+		}, 1)
+		if err != nil {
+			t.Fatalf("create %q: %v", title, err)
+		}
+		return article.ID
+	}
+	both := create("Both", "go", "golang")
+	source := create("Source", "web", "golang")
+	target := create("Target", "go")
+
+	ids := map[string]int{}
+	tags, err := s.Tag.GetAllTags()
+	if err != nil {
+		t.Fatalf("get tags: %v", err)
+	}
+	for _, tag := range tags {
+		ids[tag.Name] = tag.ID
+	}
+
+	if _, err := s.Tag.MergeTags(&model.MergeTagsRequest{SourceIDs: []int{ids["go"]}, TargetID: ids["go"]}); err == nil {
+		t.Error("merging a tag into itself succeeded")
+	}
+	if _, err := s.Tag.MergeTags(&model.MergeTagsRequest{SourceIDs: []int{ids["golang"]}, TargetID: ids["go"]}); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	// 已有目标标签的文章不会重复，源标签的位置由目标标签接替
+	want := map[int][]string{both: {"go"}, source: {"web", "go"}, target: {"go"}}
+	for id, wantTags := range want {
+		article, err := s.Article.articleRepo.GetByID(id)
+		if err != nil {
+			t.Fatalf("get article %d: %v", id, err)
+		}
+		if !reflect.DeepEqual(article.Tags, wantTags) {
+			t.Errorf("article %d tags = %v, want %v", id, article.Tags, wantTags)
+		}
+	}
+
+	tags, err = s.Tag.GetAllTags()
+	if err != nil {
+		t.Fatalf("get tags: %v", err)
+	}
+	for _, tag := range tags {
+		if tag.Name == "golang" {
+			t.Error("source tag still exists after merge")
+		}
+		if tag.Name == "go" && tag.ArticleCount != 3 {
+			t.Errorf("go article count = %d, want 3", tag.ArticleCount)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"pea-blog-backend/internal/model"
+)
+
+// GetTrash 分页列出回收站中的文章，默认按移入回收站的时间倒序
+func (s *ArticleService) GetTrash(params model.SearchParams) (*model.ArticleListResponse, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > 100 {
+		params.PageSize = 10
+	}
+	if params.SortBy == "" || params.SortBy == "created_at" {
+		params.SortBy = "deleted_at"
+	}
+	params.IncludeDrafts = true
+	params.Trashed = true
+
+	articles, total, err := s.articleRepo.GetAll(params)
+	if err != nil {
+		s.logger.Error("Failed to get trashed articles", "error", err)
+		return nil, fmt.Errorf("failed to get trashed articles")
+	}
+
+	return &model.ArticleListResponse{
+		Articles: articles,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}, nil
+}
+
+func (s *ArticleService) RestoreArticle(id int) error {
+	if err := s.articleRepo.Restore(id); err != nil {
+		if err.Error() == "article not found in trash" {
+			return err
+		}
+		s.logger.Error("Failed to restore article", "articleID", id, "error", err)
+		return fmt.Errorf("failed to restore article")
+	}
+
+	s.logger.Info("Article restored from trash", "articleID", id)
+	return nil
+}
+
+// PurgeArticle 永久删除回收站中的文章，不可恢复
+func (s *ArticleService) PurgeArticle(id int) error {
+	if err := s.articleRepo.Purge(id); err != nil {
+		if err.Error() == "article not found in trash" {
+			return err
+		}
+		s.logger.Error("Failed to purge article", "articleID", id, "error", err)
+		return fmt.Errorf("failed to purge article")
+	}
+
+	s.logger.Info("Article purged", "articleID", id)
+	return nil
+}
+
+// PurgeTrash 永久删除在回收站中超过 retention 的文章，返回删除数量
+func (s *ArticleService) PurgeTrash(retention time.Duration) (int, []error) {
+	ids, err := s.articleRepo.GetTrashedBefore(time.Now().Add(-retention))
+	if err != nil {
+		return 0, []error{fmt.Errorf("failed to find expired trash: %w", err)}
+	}
+
+	var errors []error
+	purged := 0
+	for _, id := range ids {
+		if err := s.articleRepo.Purge(id); err != nil {
+			errors = append(errors, fmt.Errorf("failed to purge article %d: %w", id, err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors
+}
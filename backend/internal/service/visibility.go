@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"pea-blog-backend/internal/model"
+	"pea-blog-backend/internal/repository"
+	"pea-blog-backend/internal/util"
+)
+
+// ArticleAccessTTL 为输入密码后获得的访问令牌的有效期
+const ArticleAccessTTL = 2 * time.Hour
+
+// checkAccess 检查读者能否查看文章：未发布和私密的文章只有管理员可见，
+// 受密码保护的文章在没有有效访问令牌时隐藏正文
+func (s *ArticleService) checkAccess(article *model.Article, includeUnpublished bool, accessToken string) error {
+	err := articleAccess(article, includeUnpublished, accessToken)
+	if err != nil && err.Error() == "article is password protected" {
+		lockArticle(article)
+		return nil
+	}
+	return err
+}
+
+// articleAccess 与 checkAccess 规则相同，但受密码保护的文章没有有效访问令牌时返回错误，
+// 用于评论、点赞等不能只隐藏正文的操作
+func articleAccess(article *model.Article, includeUnpublished bool, accessToken string) error {
+	if includeUnpublished {
+		return nil
+	}
+	if article.Status != "published" || article.Visibility == model.VisibilityPrivate {
+		return fmt.Errorf("article not found")
+	}
+	if article.Visibility == model.VisibilityPassword {
+		if articleID, err := util.ValidateArticleAccessToken(accessToken); err != nil || articleID != article.ID {
+			return fmt.Errorf("article is password protected")
+		}
+	}
+	return nil
+}
+
+// checkArticleAccess 加载文章并按 articleAccess 检查读者能否查看评论、评论或点赞
+func checkArticleAccess(articleRepo *repository.ArticleRepository, articleID int, includeUnpublished bool, accessToken string) error {
+	article, err := articleRepo.GetByIDForEdit(articleID)
+	if err != nil {
+		return fmt.Errorf("article not found")
+	}
+	return articleAccess(article, includeUnpublished, accessToken)
+}
+
+// lockArticle 隐藏正文以及由正文生成的内容，作者手写的摘要仍然返回
+func lockArticle(article *model.Article) {
+	article.Content = ""
+	article.ContentHTML = ""
+	article.TOC = nil
+	if article.SummaryAuto {
+		article.Summary = ""
+	}
+	article.Locked = true
+}
+
+// lockProtectedArticles 隐藏列表中受密码保护文章的正文
+func lockProtectedArticles(articles []model.Article) {
+	for i := range articles {
+		if articles[i].Visibility == model.VisibilityPassword {
+			lockArticle(&articles[i])
+		}
+	}
+}
+
+// UnlockArticle 校验受密码保护文章的密码，正确时返回该文章的访问令牌
+func (s *ArticleService) UnlockArticle(id int, password string) (*model.ArticleAccessToken, error) {
+	article, err := s.articleRepo.GetByIDForEdit(id)
+	if err != nil || article.Status != "published" || article.Visibility == model.VisibilityPrivate {
+		return nil, fmt.Errorf("article not found")
+	}
+	if article.Visibility != model.VisibilityPassword {
+		return nil, fmt.Errorf("article is not password protected")
+	}
+	if !util.CheckPassword(password, article.PasswordHash) {
+		s.logger.Warn("Incorrect article password", "articleID", id)
+		return nil, fmt.Errorf("incorrect password")
+	}
+
+	token, expiresAt, err := util.GenerateArticleAccessToken(article.ID, ArticleAccessTTL)
+	if err != nil {
+		s.logger.Error("Failed to generate article access token", "articleID", id, "error", err)
+		return nil, fmt.Errorf("failed to generate access token")
+	}
+	return &model.ArticleAccessToken{
+		ArticleID: article.ID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// articleVisibility 计算文章新的可见性与密码哈希。password 为空时沿用 currentHash，
+// 可见性不是 password 时清除密码
+func articleVisibility(visibility, password, currentHash string) (string, string, error) {
+	if visibility == "" {
+		visibility = model.VisibilityPublic
+	}
+	if visibility != model.VisibilityPassword {
+		return visibility, "", nil
+	}
+	if password == "" {
+		if currentHash == "" {
+			return "", "", fmt.Errorf("password is required for protected articles")
+		}
+		return visibility, currentHash, nil
+	}
+
+	hash, err := util.HashPassword(password)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to hash password")
+	}
+	return visibility, hash, nil
+}
+
+// updatedVisibility 计算更新后的可见性与密码哈希，请求中没有修改可见性和密码时返回空值
+func (s *ArticleService) updatedVisibility(id int, req model.UpdateArticleRequest) (string, string, error) {
+	if req.Visibility == nil && req.Password == nil {
+		return "", "", nil
+	}
+
+	article, err := s.articleRepo.GetByIDForEdit(id)
+	if err != nil {
+		return "", "", fmt.Errorf("article not found")
+	}
+	visibility := article.Visibility
+	if req.Visibility != nil {
+		visibility = *req.Visibility
+	}
+	password := ""
+	if req.Password != nil {
+		password = *req.Password
+	}
+	return articleVisibility(visibility, password, article.PasswordHash)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"pea-blog-backend/internal/model"
+)
+
+func TestUnlockArticle(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+	s := newTestService(t)
+	create := func(title, visibility, password string) *model.Article {
+		t.Helper()
+		article, err := s.Article.CreateArticle(model.CreateArticleRequest{
+			Title: title, Content: "secret content", Summary: "teaser", Status: "published",
+			Visibility: visibility, Password: password,
+		}, 1)
+		if err != nil {
+			t.Fatalf("create %q: %v", title, err)
+		}
+		return article
+	}
+	protected := create("Protected", model.VisibilityPassword, "hunter2")
+	other := create("Other", model.VisibilityPassword, "letmein")
+	private := create("Private", model.VisibilityPrivate, "")
+	public := create("Public", model.VisibilityPublic, "")
+
+	locked, err := s.Article.GetArticleByID(protected.ID, false, "")
+	if err != nil {
+		t.Fatalf("get locked: %v", err)
+	}
+	if !locked.Locked || locked.Content != "" || locked.ContentHTML != "" {
+		t.Errorf("locked article = locked %v, content %q, want content hidden", locked.Locked, locked.Content)
+	}
+	if locked.Summary != "teaser" {
+		t.Errorf("locked summary = %q, want the handwritten summary", locked.Summary)
+	}
+
+	if _, err := s.Article.UnlockArticle(protected.ID, "wrong"); err == nil || err.Error() != "incorrect password" {
+		t.Errorf("wrong password = %v, want incorrect password", err)
+	}
+	if _, err := s.Article.UnlockArticle(private.ID, ""); err == nil || err.Error() != "article not found" {
+		t.Errorf("unlock private = %v, want article not found", err)
+	}
+	if _, err := s.Article.UnlockArticle(public.ID, ""); err == nil || err.Error() != "article is not password protected" {
+		t.Errorf("unlock public = %v, want article is not password protected", err)
+	}
+
+	access, err := s.Article.UnlockArticle(protected.ID, "hunter2")
+	if err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	unlocked, err := s.Article.GetArticleByID(protected.ID, false, access.Token)
+	if err != nil {
+		t.Fatalf("get unlocked: %v", err)
+	}
+	if unlocked.Locked || unlocked.Content != "secret content" {
+		t.Errorf("unlocked article = locked %v, content %q, want full content", unlocked.Locked, unlocked.Content)
+	}
+
+	// 令牌只对解锁的那一篇文章有效
+	stillLocked, err := s.Article.GetArticleByID(other.ID, false, access.Token)
+	if err != nil {
+		t.Fatalf("get other: %v", err)
+	}
+	if !stillLocked.Locked {
+		t.Error("access token unlocked a different article")
+	}
+	if err := checkArticleAccess(s.Article.articleRepo, other.ID, false, access.Token); err == nil || err.Error() != "article is password protected" {
+		t.Errorf("comment access to other article = %v, want article is password protected", err)
+	}
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Local 将文件保存在本地目录，由服务自身以 URLPrefix 提供静态访问
+type Local struct {
+	dir        string
+	urlPrefix  string
+	cdnBaseURL string
+}
+
+func NewLocal(dir, urlPrefix, cdnBaseURL string) *Local {
+	return &Local{dir: dir, urlPrefix: urlPrefix, cdnBaseURL: cdnBaseURL}
+}
+
+func (s *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	// 先写临时文件再重命名，避免读到写了一半的文件
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *Local) URL(key string) string {
+	// CDN 回源到本服务，因此保留 URL 前缀
+	return s.cdnBaseURL + s.urlPrefix + "/" + key
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"pea-blog-backend/internal/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// 文件名为随机 UUID，内容不会变化，可以长期缓存
+const s3CacheControl = "public, max-age=31536000, immutable"
+
+// S3 将文件保存到 S3 兼容的对象存储（AWS S3、MinIO、R2 等）
+type S3 struct {
+	client     *minio.Client
+	bucket     string
+	prefix     string
+	baseURL    string
+	cdnBaseURL string
+}
+
+func NewS3(cfg *config.S3Config, cdnBaseURL string) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 storage driver")
+	}
+
+	lookup := minio.BucketLookupAuto
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	scheme := "https"
+	if !cfg.UseSSL {
+		scheme = "http"
+	}
+	baseURL := fmt.Sprintf("%s://%s.%s", scheme, cfg.Bucket, cfg.Endpoint)
+	if cfg.PathStyle {
+		baseURL = fmt.Sprintf("%s://%s/%s", scheme, cfg.Endpoint, cfg.Bucket)
+	}
+
+	return &S3{
+		client:     client,
+		bucket:     cfg.Bucket,
+		prefix:     cfg.Prefix,
+		baseURL:    baseURL,
+		cdnBaseURL: cdnBaseURL,
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, s.objectKey(key), r, size, minio.PutObjectOptions{
+		ContentType:  contentType,
+		CacheControl: s3CacheControl,
+	})
+	return err
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, s.objectKey(key), minio.RemoveObjectOptions{})
+}
+
+func (s *S3) URL(key string) string {
+	if s.cdnBaseURL != "" {
+		return s.cdnBaseURL + "/" + s.objectKey(key)
+	}
+	return s.baseURL + "/" + s.objectKey(key)
+}
+
+func (s *S3) objectKey(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"pea-blog-backend/internal/config"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Storage 保存上传的媒体文件，key 为相对路径（如 "3f2c....png"）
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL 返回文件对外访问的地址，配置了 CDN 时使用 CDN 域名
+	URL(key string) string
+}
+
+// New 根据配置创建存储后端
+func New(cfg *config.StorageConfig, upload *config.UploadConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocal(upload.Dir, upload.URLPrefix, cfg.CDNBaseURL), nil
+	case DriverS3:
+		return NewS3(&cfg.S3, cfg.CDNBaseURL)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
+
+// cleanKey 规范化 key，拒绝绝对路径和跳出根目录的路径
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCleanKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "photo.png", want: "photo.png"},
+		{key: "2024/photo-320w.webp", want: "2024/photo-320w.webp"},
+		{key: "/photo.png", want: "photo.png"},
+		{key: "", wantErr: true},
+		{key: "/", wantErr: true},
+		{key: "../photo.png", wantErr: true},
+		{key: "a/../../photo.png", wantErr: true},
+		{key: "a/../photo.png", wantErr: true},
+		{key: "..\\photo.png", wantErr: true},
+		{key: "a//photo.png", wantErr: true},
+		{key: "./photo.png", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := cleanKey(tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("cleanKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("cleanKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLocalRejectsTraversal(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "uploads")
+	s := NewLocal(dir, "/uploads", "")
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "../escape.txt", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Put outside the upload directory succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape.txt")); !os.IsNotExist(err) {
+		t.Errorf("file written outside the upload directory: %v", err)
+	}
+
+	outside := filepath.Join(root, "keep.txt")
+	if err := os.WriteFile(outside, []byte("keep"), 0o644); err != nil {
+		t.Fatalf("write outside file: %v", err)
+	}
+	if err := s.Delete(ctx, "../keep.txt"); err == nil {
+		t.Error("Delete outside the upload directory succeeded")
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the upload directory was removed: %v", err)
+	}
+
+	if err := s.Put(ctx, "photo.png", strings.NewReader("x"), 1, "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "photo.png")); err != nil {
+		t.Errorf("stored file missing: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package transfer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"pea-blog-backend/internal/model"
+)
+
+const (
+	FormatZip  = "zip"
+	FormatJSON = "json"
+)
+
+// DumpVersion 标识 JSON 导出格式的版本，导入时据此兼容旧格式
+const DumpVersion = 1
+
+// Dump 是 JSON 格式的完整导出
+type Dump struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Articles   []model.Article `json:"articles"`
+}
+
+// WriteZip 将每篇文章写成一个带 front matter 的 Markdown 文件并打包
+func WriteZip(w io.Writer, articles []model.Article) error {
+	zw := zip.NewWriter(w)
+
+	for _, article := range articles {
+		data, err := EncodeMarkdown(article)
+		if err != nil {
+			return err
+		}
+
+		header := &zip.FileHeader{
+			Name:     "articles/" + MarkdownFileName(article),
+			Method:   zip.Deflate,
+			Modified: article.UpdatedAt,
+		}
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func WriteJSON(w io.Writer, articles []model.Article) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Dump{
+		Version:    DumpVersion,
+		ExportedAt: time.Now(),
+		Articles:   articles,
+	})
+}
@@ -0,0 +1,224 @@
+package transfer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"pea-blog-backend/internal/model"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const tomlFrontMatterDelimiter = "+++"
+
+var (
+	exportPrefixPattern = regexp.MustCompile(`^\d+-`)
+	jekyllPostPattern   = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+)
+
+var frontMatterTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// DecodeMarkdown 解析带 front matter 的 Markdown 或 HTML 文件。
+// 除本站导出的字段外，同时识别 Hugo（YAML/TOML，draft、lastmod 等）与
+// Jekyll（published、文件名中的日期、_drafts 目录）的常用字段。
+func DecodeMarkdown(source string, data []byte) (*Entry, error) {
+	header, format, body, err := splitFrontMatter(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	meta := map[string]interface{}{}
+	if len(header) > 0 {
+		if format == tomlFrontMatterDelimiter {
+			err = toml.Unmarshal(header, &meta)
+		} else {
+			err = yaml.Unmarshal(header, &meta)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid front matter: %w", source, err)
+		}
+	}
+
+	ext := strings.ToLower(path.Ext(source))
+	if ext == ".html" || ext == ".htm" {
+		body, err = HTMLToMarkdown(body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+	}
+
+	article := model.Article{
+		Title:   metaString(meta, "title"),
+		Slug:    metaString(meta, "slug"),
+		Content: body,
+		Summary: metaString(meta, "summary", "description", "excerpt"),
+		Tags:    append(metaStrings(meta, "tags"), metaStrings(meta, "categories", "category")...),
+	}
+
+	name := strings.TrimSuffix(path.Base(source), path.Ext(source))
+	if name == "index" {
+		// Hugo page bundle：posts/my-post/index.md
+		name = path.Base(path.Dir(source))
+	}
+	fileDate := time.Time{}
+	if m := jekyllPostPattern.FindStringSubmatch(name); m != nil {
+		fileDate, _ = time.Parse("2006-01-02", m[1])
+		name = m[2]
+	}
+	if article.Title == "" {
+		article.Title = exportPrefixPattern.ReplaceAllString(name, "")
+	}
+
+	date := metaTime(meta, "published_at", "publishDate", "date")
+	if date == nil && !fileDate.IsZero() {
+		date = &fileDate
+	}
+	if created := metaTime(meta, "created_at", "date"); created != nil {
+		article.CreatedAt = *created
+	} else if date != nil {
+		article.CreatedAt = *date
+	}
+	if updated := metaTime(meta, "updated_at", "lastmod", "last_modified_at"); updated != nil {
+		article.UpdatedAt = *updated
+	}
+
+	article.Status = metaString(meta, "status")
+	if article.Status == "" {
+		switch {
+		case metaBool(meta, "draft") || strings.Contains(source, "_drafts/"):
+			article.Status = "draft"
+		case meta["published"] == false:
+			article.Status = "draft"
+		case date != nil && date.After(time.Now()):
+			article.Status = "scheduled"
+		case date != nil:
+			article.Status = "published"
+		}
+	}
+	if article.Status == "published" || article.Status == "scheduled" {
+		article.PublishedAt = date
+	}
+
+	if cover := metaString(meta, "cover_image", "image", "featured_image", "thumbnail", "cover"); cover != "" {
+		article.CoverImage = &cover
+	}
+
+	author := metaString(meta, "author")
+	if author == "" {
+		if authors := metaStrings(meta, "authors"); len(authors) > 0 {
+			author = authors[0]
+		}
+	}
+
+	return &Entry{Source: source, Author: author, Article: article}, nil
+}
+
+// splitFrontMatter 拆分 "---"（YAML）或 "+++"（TOML）包围的头部与正文
+func splitFrontMatter(data []byte) ([]byte, string, string, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var delimiter string
+	switch {
+	case strings.HasPrefix(text, frontMatterDelimiter+"\n"):
+		delimiter = frontMatterDelimiter
+	case strings.HasPrefix(text, tomlFrontMatterDelimiter+"\n"):
+		delimiter = tomlFrontMatterDelimiter
+	default:
+		return nil, "", text, nil
+	}
+
+	rest := text[len(delimiter)+1:]
+	end := strings.Index(rest, "\n"+delimiter+"\n")
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n"+delimiter) {
+			return nil, "", "", fmt.Errorf("unterminated front matter")
+		}
+		end = len(rest) - len(delimiter) - 1
+	}
+
+	header := rest[:end]
+	body := ""
+	if bodyStart := end + len(delimiter) + 2; bodyStart < len(rest) {
+		body = rest[bodyStart:]
+	}
+	return []byte(header), delimiter, strings.TrimLeft(body, "\n"), nil
+}
+
+func metaString(meta map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch v := meta[key].(type) {
+		case string:
+			if s := strings.TrimSpace(v); s != "" {
+				return s
+			}
+		case map[string]interface{}:
+			// Hugo 主题常见写法：cover: {image: ...}、author: {name: ...}
+			if s := metaString(v, "image", "name"); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func metaStrings(meta map[string]interface{}, keys ...string) []string {
+	var values []string
+	for _, key := range keys {
+		switch v := meta[key].(type) {
+		case string:
+			// Jekyll 允许以空格分隔的字符串，同时兼容逗号分隔
+			for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+				values = append(values, item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if s := strings.TrimSpace(fmt.Sprint(item)); s != "" {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+	return values
+}
+
+func metaBool(meta map[string]interface{}, key string) bool {
+	switch v := meta[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+func metaTime(meta map[string]interface{}, keys ...string) *time.Time {
+	for _, key := range keys {
+		value, ok := meta[key]
+		if !ok || value == nil {
+			continue
+		}
+		if t, ok := value.(time.Time); ok {
+			return &t
+		}
+		text := strings.TrimSpace(fmt.Sprint(value))
+		for _, layout := range frontMatterTimeLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
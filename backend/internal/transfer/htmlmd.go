@@ -0,0 +1,437 @@
+package transfer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLinesPattern  = regexp.MustCompile(`\n{3,}`)
+	blockTagPattern    = regexp.MustCompile(`(?i)^<(p|div|h[1-6]|ul|ol|pre|blockquote|table|figure|hr)[\s>/]`)
+	markdownEscapeChar = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+)
+
+// HTMLToMarkdown 将文章 HTML 转换为 Markdown，覆盖博客内容中常见的标签，
+// 无法表达的标签只保留其文本内容。
+func HTMLToMarkdown(source string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(source), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	c := &htmlConverter{}
+	for _, node := range nodes {
+		c.block(node)
+	}
+
+	out := blankLinesPattern.ReplaceAllString(c.buf.String(), "\n\n")
+	return strings.TrimSpace(out) + "\n", nil
+}
+
+// AutoParagraph 模拟 WordPress 的 wpautop：按空行切分段落，
+// 不以块级标签开头的段落包裹 <p>，段内换行转为 <br>
+func AutoParagraph(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, para := range strings.Split(source, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		if blockTagPattern.MatchString(para) {
+			b.WriteString(para + "\n")
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(para, "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// HTMLToText 提取 HTML 中的纯文本并折叠空白
+func HTMLToText(source string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(source), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return source
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		b.WriteString(textContent(node))
+		b.WriteString(" ")
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+type htmlConverter struct {
+	buf       strings.Builder
+	listDepth int
+}
+
+func (c *htmlConverter) ensureBlankLine() {
+	s := c.buf.String()
+	if s == "" || strings.HasSuffix(s, "\n\n") {
+		return
+	}
+	if strings.HasSuffix(s, "\n") {
+		c.buf.WriteString("\n")
+	} else {
+		c.buf.WriteString("\n\n")
+	}
+}
+
+func (c *htmlConverter) block(n *html.Node) {
+	if n.Type == html.TextNode {
+		text := c.inlineText(n.Data)
+		if strings.TrimSpace(text) == "" {
+			// 块级元素之间的空白不输出，行内元素之间保留一个空格
+			if text == "" || c.buf.Len() == 0 || strings.HasSuffix(c.buf.String(), "\n") {
+				return
+			}
+		}
+		c.buf.WriteString(text)
+		return
+	}
+	if n.Type != html.ElementNode {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			c.block(child)
+		}
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Iframe:
+		return
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		c.ensureBlankLine()
+		c.buf.WriteString(strings.Repeat("#", level) + " " + strings.TrimSpace(c.inline(n)))
+		c.ensureBlankLine()
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Figure, atom.Header, atom.Footer:
+		c.ensureBlankLine()
+		if hasBlockChild(n) {
+			for child := n.FirstChild; child != nil; child = child.NextSibling {
+				c.block(child)
+			}
+		} else {
+			c.buf.WriteString(strings.TrimSpace(c.inline(n)))
+		}
+		c.ensureBlankLine()
+	case atom.Figcaption:
+		c.ensureBlankLine()
+		c.buf.WriteString("*" + strings.TrimSpace(c.inline(n)) + "*")
+		c.ensureBlankLine()
+	case atom.Hr:
+		c.ensureBlankLine()
+		c.buf.WriteString("---")
+		c.ensureBlankLine()
+	case atom.Pre:
+		c.ensureBlankLine()
+		c.buf.WriteString(c.codeBlock(n))
+		c.ensureBlankLine()
+	case atom.Blockquote:
+		inner := &htmlConverter{}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			inner.block(child)
+		}
+		c.ensureBlankLine()
+		for _, line := range strings.Split(strings.TrimSpace(inner.buf.String()), "\n") {
+			if line == "" {
+				c.buf.WriteString(">\n")
+			} else {
+				c.buf.WriteString("> " + line + "\n")
+			}
+		}
+		c.ensureBlankLine()
+	case atom.Ul, atom.Ol:
+		c.list(n)
+	case atom.Table:
+		c.ensureBlankLine()
+		c.buf.WriteString(c.table(n))
+		c.ensureBlankLine()
+	default:
+		c.buf.WriteString(c.inlineNode(n))
+	}
+}
+
+func (c *htmlConverter) list(n *html.Node) {
+	if c.listDepth == 0 {
+		c.ensureBlankLine()
+	} else if !strings.HasSuffix(c.buf.String(), "\n") {
+		c.buf.WriteString("\n")
+	}
+
+	indent := strings.Repeat("  ", c.listDepth)
+	c.listDepth++
+	index := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", index)
+		}
+		index++
+
+		c.buf.WriteString(indent + marker)
+		var text strings.Builder
+		for child := li.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && (child.DataAtom == atom.Ul || child.DataAtom == atom.Ol) {
+				c.buf.WriteString(strings.TrimSpace(text.String()))
+				text.Reset()
+				c.list(child)
+				continue
+			}
+			if child.Type == html.ElementNode && child.DataAtom == atom.P {
+				text.WriteString(c.inline(child) + " ")
+				continue
+			}
+			text.WriteString(c.inlineNode(child))
+		}
+		if t := strings.TrimSpace(text.String()); t != "" {
+			c.buf.WriteString(t)
+		}
+		if !strings.HasSuffix(c.buf.String(), "\n") {
+			c.buf.WriteString("\n")
+		}
+	}
+	c.listDepth--
+
+	if c.listDepth == 0 {
+		c.ensureBlankLine()
+	}
+}
+
+func (c *htmlConverter) codeBlock(n *html.Node) string {
+	lang := ""
+	target := n
+	if code := firstChildElement(n, atom.Code); code != nil {
+		target = code
+		lang = codeLanguage(code)
+	}
+	if lang == "" {
+		lang = codeLanguage(n)
+	}
+
+	code := strings.TrimRight(textContent(target), "\n")
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+func (c *htmlConverter) table(n *html.Node) string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if child.DataAtom == atom.Tr {
+				var cells []string
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+						text := strings.TrimSpace(c.inline(cell))
+						cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+					}
+				}
+				rows = append(rows, cells)
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (c *htmlConverter) inline(n *html.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(c.inlineNode(child))
+	}
+	return b.String()
+}
+
+func (c *htmlConverter) inlineNode(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return c.inlineText(n.Data)
+	}
+	if n.Type != html.ElementNode {
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript:
+		return ""
+	case atom.Br:
+		return "  \n"
+	case atom.Strong, atom.B:
+		return wrapInline(c.inline(n), "**")
+	case atom.Em, atom.I:
+		return wrapInline(c.inline(n), "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrapInline(c.inline(n), "~~")
+	case atom.Code, atom.Kbd, atom.Tt:
+		text := textContent(n)
+		if !strings.Contains(text, "`") {
+			return "`" + text + "`"
+		}
+		// 内容含反引号时用双反引号包裹，并加空格避免与开头或结尾的反引号相连
+		return "`` " + text + " ``"
+	case atom.A:
+		text := strings.TrimSpace(c.inline(n))
+		href := attr(n, "href")
+		if href == "" {
+			return text
+		}
+		if text == "" {
+			text = href
+		}
+		return "[" + text + "](" + href + ")"
+	case atom.Img:
+		return "![" + attr(n, "alt") + "](" + attr(n, "src") + ")"
+	case atom.P, atom.Div, atom.Ul, atom.Ol, atom.Pre, atom.Blockquote, atom.Table,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Hr:
+		inner := &htmlConverter{listDepth: c.listDepth}
+		inner.block(n)
+		return "\n\n" + strings.TrimSpace(inner.buf.String()) + "\n\n"
+	default:
+		return c.inline(n)
+	}
+}
+
+// inlineText 折叠 HTML 中无意义的空白并转义 Markdown 特殊字符
+func (c *htmlConverter) inlineText(text string) string {
+	collapsed := strings.Join(strings.Fields(text), " ")
+	if collapsed == "" {
+		if text != "" {
+			return " "
+		}
+		return ""
+	}
+
+	collapsed = markdownEscapeChar.Replace(collapsed)
+	if strings.TrimLeft(text, " \t\n\r") != text {
+		collapsed = " " + collapsed
+	}
+	if strings.TrimRight(text, " \t\n\r") != text {
+		collapsed += " "
+	}
+	return collapsed
+}
+
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	prefix := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	suffix := text[len(strings.TrimRight(text, " ")):]
+	return prefix + marker + trimmed + marker + suffix
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch child.DataAtom {
+		case atom.P, atom.Div, atom.Ul, atom.Ol, atom.Pre, atom.Blockquote, atom.Table,
+			atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Hr, atom.Figure, atom.Figcaption:
+			return true
+		}
+	}
+	return false
+}
+
+func firstChildElement(n *html.Node, a atom.Atom) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.DataAtom == a {
+			return child
+		}
+	}
+	return nil
+}
+
+// codeLanguage 从 class="language-go"、"lang-go" 或 SyntaxHighlighter 的 "brush: go" 中提取语言
+func codeLanguage(n *html.Node) string {
+	class := attr(n, "class")
+	for _, field := range strings.Fields(strings.ReplaceAll(class, ";", " ")) {
+		switch {
+		case strings.HasPrefix(field, "language-"):
+			return strings.TrimPrefix(field, "language-")
+		case strings.HasPrefix(field, "lang-"):
+			return strings.TrimPrefix(field, "lang-")
+		}
+	}
+	if idx := strings.Index(class, "brush:"); idx >= 0 {
+		if fields := strings.Fields(class[idx+len("brush:"):]); len(fields) > 0 {
+			return strings.TrimSuffix(fields[0], ";")
+		}
+	}
+	return attr(n, "data-lang")
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package transfer
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestHTMLToMarkdownGolden 把 testdata/htmlmd/*.html 转换后与同名 .md 比较，
+// 修改转换规则后用 go test -run HTMLToMarkdown -update 重新生成
+func TestHTMLToMarkdownGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "htmlmd", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no golden inputs found")
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".html")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := HTMLToMarkdown(string(source))
+			if err != nil {
+				t.Fatalf("HTMLToMarkdown: %v", err)
+			}
+
+			golden := strings.TrimSuffix(input, ".html") + ".md"
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("HTMLToMarkdown(%s) mismatch\n--- got ---\n%s\n--- want ---\n%s", input, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package transfer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"pea-blog-backend/internal/model"
+)
+
+// 解压后允许的最大总大小，防止 zip 炸弹
+const maxArchiveSize = 256 << 20
+
+var ErrUnsupportedFormat = errors.New("unsupported import format")
+
+// Entry 是从导入文件中解析出的一篇文章
+type Entry struct {
+	Source   string
+	Author   string
+	Article  model.Article
+	Comments []Comment
+}
+
+// Comment 是随文章一起导入的评论，Key/ParentKey 为来源系统中的 ID，用于还原回复关系
+type Comment struct {
+	Key         int
+	ParentKey   int
+	AuthorName  string
+	AuthorEmail string
+	Content     string
+	CreatedAt   time.Time
+}
+
+// Parse 根据文件扩展名解析导入文件：
+// ZIP（本站导出或 Hugo/Jekyll 内容目录）、Markdown/HTML 单篇、JSON 导出以及 WordPress WXR
+func Parse(filename string, data []byte) ([]Entry, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".zip":
+		return parseZip(data)
+	case ".md", ".markdown", ".html", ".htm":
+		if skipContentFile(filename) {
+			return nil, nil
+		}
+		entry, err := DecodeMarkdown(filename, data)
+		if err != nil {
+			return nil, err
+		}
+		return []Entry{*entry}, nil
+	case ".json":
+		return parseJSON(filename, data)
+	case ".xml":
+		return ParseWXR(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func parseJSON(source string, data []byte) ([]Entry, error) {
+	var articles []model.Article
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &articles); err != nil {
+			return nil, fmt.Errorf("%s: invalid JSON: %w", source, err)
+		}
+	} else {
+		var dump Dump
+		if err := json.Unmarshal(trimmed, &dump); err != nil {
+			return nil, fmt.Errorf("%s: invalid JSON: %w", source, err)
+		}
+		if dump.Version > DumpVersion {
+			return nil, fmt.Errorf("%s: unsupported export version %d", source, dump.Version)
+		}
+		articles = dump.Articles
+	}
+
+	entries := make([]Entry, 0, len(articles))
+	for i, article := range articles {
+		entry := Entry{
+			Source:  fmt.Sprintf("%s#%d", source, i+1),
+			Article: article,
+		}
+		if article.Author != nil {
+			entry.Author = article.Author.Username
+		}
+		entry.Article.ID = 0
+		entry.Article.Author = nil
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseZip(data []byte) ([]Entry, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	var entries []Entry
+	var total int64
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		switch strings.ToLower(path.Ext(file.Name)) {
+		case ".md", ".markdown", ".html", ".htm", ".json", ".xml":
+		default:
+			continue
+		}
+		if skipContentFile(file.Name) {
+			continue
+		}
+
+		content, err := readZipFile(file, maxArchiveSize-total)
+		if err != nil {
+			return nil, err
+		}
+		total += int64(len(content))
+
+		parsed, err := Parse(file.Name, content)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, parsed...)
+	}
+
+	return entries, nil
+}
+
+// skipContentFile 跳过隐藏文件、Hugo 的栏目列表页以及静态站点的构建产物
+func skipContentFile(name string) bool {
+	for _, part := range strings.Split(path.Clean(name), "/") {
+		if strings.HasPrefix(part, ".") || part == "_site" || part == "public" || part == "node_modules" || part == "__MACOSX" {
+			return true
+		}
+	}
+	return path.Base(name) == "_index.md"
+}
+
+func readZipFile(file *zip.File, limit int64) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file.Name, err)
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("archive is too large")
+	}
+	return content, nil
+}
@@ -0,0 +1,95 @@
+package transfer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"pea-blog-backend/internal/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// FrontMatter 是导出 Markdown 文件头部的 YAML 元数据
+type FrontMatter struct {
+	Title       string     `yaml:"title"`
+	Slug        string     `yaml:"slug,omitempty"`
+	Summary     string     `yaml:"summary,omitempty"`
+	Tags        []string   `yaml:"tags"`
+	Status      string     `yaml:"status"`
+	Author      string     `yaml:"author,omitempty"`
+	CoverImage  string     `yaml:"cover_image,omitempty"`
+	PublishedAt *time.Time `yaml:"published_at,omitempty"`
+	CreatedAt   *time.Time `yaml:"created_at,omitempty"`
+	UpdatedAt   *time.Time `yaml:"updated_at,omitempty"`
+}
+
+// EncodeMarkdown 将文章编码为带 YAML front matter 的 Markdown
+func EncodeMarkdown(article model.Article) ([]byte, error) {
+	fm := FrontMatter{
+		Title:       article.Title,
+		Slug:        article.Slug,
+		Summary:     article.Summary,
+		Tags:        article.Tags,
+		Status:      article.Status,
+		PublishedAt: article.PublishedAt,
+		CreatedAt:   &article.CreatedAt,
+		UpdatedAt:   &article.UpdatedAt,
+	}
+	if fm.Tags == nil {
+		fm.Tags = []string{}
+	}
+	if article.SummaryAuto {
+		// 自动摘要在导入时重新生成
+		fm.Summary = ""
+	}
+	if article.Author != nil {
+		fm.Author = article.Author.Username
+	}
+	if article.CoverImage != nil {
+		fm.CoverImage = *article.CoverImage
+	}
+
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode front matter: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(header)
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(article.Content)
+	if !strings.HasSuffix(article.Content, "\n") {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// MarkdownFileName 生成导出文件名，保留中文等字符，仅替换文件系统不允许的字符
+func MarkdownFileName(article model.Article) string {
+	var b strings.Builder
+	count := 0
+	for _, r := range strings.TrimSpace(article.Title) {
+		if count >= 80 {
+			break
+		}
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), unicode.IsSpace(r), unicode.IsControl(r):
+			b.WriteRune('-')
+		default:
+			b.WriteRune(r)
+		}
+		count++
+	}
+
+	name := strings.Trim(b.String(), "-.")
+	if name == "" {
+		name = "untitled"
+	}
+	return fmt.Sprintf("%04d-%s.md", article.ID, name)
+}
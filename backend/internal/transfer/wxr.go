@@ -0,0 +1,212 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"pea-blog-backend/internal/model"
+)
+
+// WordPress 导出文件（WXR）的结构，只声明导入需要的字段。
+// 元素名不绑定命名空间，以兼容不同版本的 wp 命名空间 URI。
+type wxrDocument struct {
+	Channel struct {
+		Items []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title         string        `xml:"title"`
+	PubDate       string        `xml:"pubDate"`
+	Creator       string        `xml:"creator"`
+	Encoded       []wxrEncoded  `xml:"encoded"`
+	PostID        string        `xml:"post_id"`
+	PostName      string        `xml:"post_name"`
+	AttachmentURL string        `xml:"attachment_url"`
+	PostDate      string        `xml:"post_date"`
+	PostDateGMT   string        `xml:"post_date_gmt"`
+	Status        string        `xml:"status"`
+	PostType      string        `xml:"post_type"`
+	Categories    []wxrCategory `xml:"category"`
+	PostMeta      []wxrPostMeta `xml:"postmeta"`
+	Comments      []wxrComment  `xml:"comment"`
+}
+
+// content:encoded 与 excerpt:encoded 同名，只能靠命名空间区分
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"`
+	Name   string `xml:",chardata"`
+}
+
+type wxrPostMeta struct {
+	Key   string `xml:"meta_key"`
+	Value string `xml:"meta_value"`
+}
+
+type wxrComment struct {
+	ID          string `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Date        string `xml:"comment_date"`
+	Content     string `xml:"comment_content"`
+	Approved    string `xml:"comment_approved"`
+	Type        string `xml:"comment_type"`
+	Parent      string `xml:"comment_parent"`
+}
+
+const wxrTimeLayout = "2006-01-02 15:04:05"
+
+// ParseWXR 解析 WordPress 导出的 WXR 文件，导入文章（post）、标签、分类、
+// 已审核的评论及其回复关系，正文从 HTML 转换为 Markdown
+func ParseWXR(data []byte) ([]Entry, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	var doc wxrDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid WXR file: %w", err)
+	}
+
+	// 特色图片以附件 ID 形式存放在 _thumbnail_id 中，先建立附件 ID 到 URL 的映射
+	attachments := map[string]string{}
+	for _, item := range doc.Channel.Items {
+		if item.PostType == "attachment" && item.AttachmentURL != "" {
+			attachments[strings.TrimSpace(item.PostID)] = strings.TrimSpace(item.AttachmentURL)
+		}
+	}
+
+	var entries []Entry
+	for _, item := range doc.Channel.Items {
+		if item.PostType != "post" {
+			continue
+		}
+		postID := strings.TrimSpace(item.PostID)
+
+		content, excerpt := "", ""
+		for _, encoded := range item.Encoded {
+			if strings.Contains(encoded.XMLName.Space, "excerpt") {
+				excerpt = encoded.Value
+			} else {
+				content = encoded.Value
+			}
+		}
+
+		markdown, err := HTMLToMarkdown(AutoParagraph(content))
+		if err != nil {
+			return nil, fmt.Errorf("post %s: %w", postID, err)
+		}
+
+		article := model.Article{
+			Title:   strings.TrimSpace(item.Title),
+			Slug:    wxrSlug(item.PostName),
+			Content: markdown,
+			Summary: HTMLToText(excerpt),
+			Tags:    []string{},
+		}
+		for _, category := range item.Categories {
+			if category.Domain == "post_tag" || category.Domain == "category" {
+				name := strings.TrimSpace(category.Name)
+				if name != "" && name != "Uncategorized" {
+					article.Tags = append(article.Tags, name)
+				}
+			}
+		}
+
+		for _, meta := range item.PostMeta {
+			if meta.Key == "_thumbnail_id" {
+				if url, ok := attachments[strings.TrimSpace(meta.Value)]; ok {
+					article.CoverImage = &url
+				}
+			}
+		}
+
+		date := parseWXRTime(item.PostDateGMT, item.PostDate, item.PubDate)
+		if date != nil {
+			article.CreatedAt = *date
+			article.UpdatedAt = *date
+		}
+		switch item.Status {
+		case "publish":
+			article.Status = "published"
+			article.PublishedAt = date
+		case "future":
+			article.Status = "scheduled"
+			article.PublishedAt = date
+		default:
+			// draft、pending、private 等统一作为草稿
+			article.Status = "draft"
+		}
+
+		entry := Entry{
+			Source:  "wxr#" + postID,
+			Author:  item.Creator,
+			Article: article,
+		}
+		for _, c := range item.Comments {
+			commentType := strings.TrimSpace(c.Type)
+			if strings.TrimSpace(c.Approved) != "1" || (commentType != "" && commentType != "comment") {
+				continue
+			}
+			text, err := HTMLToMarkdown(AutoParagraph(c.Content))
+			if err != nil {
+				continue
+			}
+			key, _ := strconv.Atoi(strings.TrimSpace(c.ID))
+			parentKey, _ := strconv.Atoi(strings.TrimSpace(c.Parent))
+			comment := Comment{
+				Key:         key,
+				ParentKey:   parentKey,
+				AuthorName:  strings.TrimSpace(c.Author),
+				AuthorEmail: strings.TrimSpace(c.AuthorEmail),
+				Content:     strings.TrimSpace(text),
+			}
+			if t := parseWXRTime(strings.TrimSpace(c.DateGMT), strings.TrimSpace(c.Date), ""); t != nil {
+				comment.CreatedAt = *t
+			}
+			entry.Comments = append(entry.Comments, comment)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseWXRTime 依次尝试 GMT 时间、站点本地时间与 RSS 的 pubDate；
+// 草稿的时间为 "0000-00-00 00:00:00"，视为无效
+func parseWXRTime(gmt, local, pubDate string) *time.Time {
+	for _, value := range []string{gmt, local} {
+		if value == "" || strings.HasPrefix(value, "0000") {
+			continue
+		}
+		if t, err := time.Parse(wxrTimeLayout, value); err == nil {
+			return &t
+		}
+	}
+	if pubDate != "" {
+		if t, err := time.Parse(time.RFC1123Z, pubDate); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// wxrSlug 解码 WordPress 的 post_name，非 ASCII 字符在其中是百分号编码的
+func wxrSlug(postName string) string {
+	postName = strings.TrimSpace(postName)
+	if decoded, err := url.PathUnescape(postName); err == nil {
+		return decoded
+	}
+	return postName
+}
@@ -1,6 +1,10 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
@@ -40,6 +44,18 @@ func getJWTExpireHours() time.Duration {
 	return time.Duration(hours) * time.Hour
 }
 
+func getRefreshExpireHours() time.Duration {
+	hoursStr := os.Getenv("JWT_REFRESH_EXPIRE_HOURS")
+	if hoursStr == "" {
+		return 30 * 24 * time.Hour // 默认30天
+	}
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil || hours <= 0 {
+		return 30 * 24 * time.Hour // 默认30天
+	}
+	return time.Duration(hours) * time.Hour
+}
+
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
@@ -50,7 +66,8 @@ func CheckPassword(password, hash string) bool {
 	return err == nil
 }
 
-func GenerateJWT(userID int, username, role string) (string, error) {
+// GenerateJWT 生成 access token，sessionID 写入 jti 声明用于服务端会话校验
+func GenerateJWT(userID int, username, role, sessionID string) (string, error) {
 	secret, err := getJWTSecret()
 	if err != nil {
 		return "", err
@@ -64,6 +81,7 @@ func GenerateJWT(userID int, username, role string) (string, error) {
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(getJWTExpireHours())),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "pea-blog",
+			ID:        sessionID,
 		},
 	}
 
@@ -93,6 +111,26 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 	if !token.Valid {
 		return nil, fmt.Errorf("invalid token")
 	}
+	// access token 不带 audience；预览令牌与文章访问令牌使用同一个密钥签名，不能当作登录凭证
+	if len(claims.Audience) > 0 || claims.UserID <= 0 {
+		return nil, fmt.Errorf("invalid token")
+	}
 
 	return claims, nil
-}
\ No newline at end of file
+}
+
+// GenerateRefreshToken 生成随机的 refresh token，返回明文、哈希值和过期时间。
+// 数据库中只保存哈希值。
+func GenerateRefreshToken() (token, hash string, expiresAt time.Time, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", time.Time{}, err
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), time.Now().Add(getRefreshExpireHours()), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
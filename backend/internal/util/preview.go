@@ -0,0 +1,83 @@
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 文章令牌的 audience，用于区分预览令牌、密码访问令牌与 access token，几种令牌使用同一个密钥签名
+const (
+	previewAudience       = "article-preview"
+	articleAccessAudience = "article-access"
+)
+
+type ArticleTokenClaims struct {
+	ArticleID int `json:"articleId"`
+	jwt.RegisteredClaims
+}
+
+// GeneratePreviewToken 为文章生成有效期为 ttl 的预览令牌，持有者无需登录即可查看未发布的文章
+func GeneratePreviewToken(articleID int, ttl time.Duration) (string, time.Time, error) {
+	return generateArticleToken(previewAudience, articleID, ttl)
+}
+
+// ValidatePreviewToken 校验预览令牌并返回其对应的文章 ID
+func ValidatePreviewToken(tokenString string) (int, error) {
+	return validateArticleToken(previewAudience, tokenString)
+}
+
+// GenerateArticleAccessToken 为输入了正确密码的读者生成受密码保护文章的访问令牌
+func GenerateArticleAccessToken(articleID int, ttl time.Duration) (string, time.Time, error) {
+	return generateArticleToken(articleAccessAudience, articleID, ttl)
+}
+
+// ValidateArticleAccessToken 校验访问令牌并返回其对应的文章 ID
+func ValidateArticleAccessToken(tokenString string) (int, error) {
+	return validateArticleToken(articleAccessAudience, tokenString)
+}
+
+func generateArticleToken(audience string, articleID int, ttl time.Duration) (string, time.Time, error) {
+	secret, err := getJWTSecret()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := ArticleTokenClaims{
+		ArticleID: articleID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "pea-blog",
+			Audience:  jwt.ClaimStrings{audience},
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+func validateArticleToken(audience, tokenString string) (int, error) {
+	secret, err := getJWTSecret()
+	if err != nil {
+		return 0, err
+	}
+
+	claims := &ArticleTokenClaims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(audience), jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, err
+	}
+	if claims.ArticleID <= 0 {
+		return 0, fmt.Errorf("invalid article token")
+	}
+	return claims.ArticleID, nil
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArticleTokenAudience(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+
+	preview, _, err := GeneratePreviewToken(7, time.Hour)
+	if err != nil {
+		t.Fatalf("generate preview token: %v", err)
+	}
+	access, _, err := GenerateArticleAccessToken(7, time.Hour)
+	if err != nil {
+		t.Fatalf("generate access token: %v", err)
+	}
+	login, err := GenerateJWT(1, "admin", "admin", "session")
+	if err != nil {
+		t.Fatalf("generate jwt: %v", err)
+	}
+
+	if id, err := ValidatePreviewToken(preview); err != nil || id != 7 {
+		t.Errorf("ValidatePreviewToken(preview) = %d, %v, want 7", id, err)
+	}
+	if id, err := ValidateArticleAccessToken(access); err != nil || id != 7 {
+		t.Errorf("ValidateArticleAccessToken(access) = %d, %v, want 7", id, err)
+	}
+
+	// 几种令牌使用同一个密钥签名，只能靠 audience 区分
+	if _, err := ValidatePreviewToken(access); err == nil {
+		t.Error("article access token accepted as a preview token")
+	}
+	if _, err := ValidatePreviewToken(login); err == nil {
+		t.Error("login token accepted as a preview token")
+	}
+	if _, err := ValidateArticleAccessToken(preview); err == nil {
+		t.Error("preview token accepted as an article access token")
+	}
+	if _, err := ValidateJWT(preview); err == nil {
+		t.Error("preview token accepted as a login token")
+	}
+	if _, err := ValidateJWT(access); err == nil {
+		t.Error("article access token accepted as a login token")
+	}
+	if _, err := ValidateJWT(login); err != nil {
+		t.Errorf("ValidateJWT(login) = %v", err)
+	}
+
+	expired, _, err := GeneratePreviewToken(7, -time.Minute)
+	if err != nil {
+		t.Fatalf("generate expired token: %v", err)
+	}
+	if _, err := ValidatePreviewToken(expired); err == nil {
+		t.Error("expired preview token accepted")
+	}
+}
@@ -0,0 +1,173 @@
+package util
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 高亮标记，先在纯文本中标记再整体转义，避免正文中的 HTML 被当作标签输出
+const (
+	markOpen  = "\x00mark\x00"
+	markClose = "\x00/mark\x00"
+)
+
+// SplitSearchTerms 按空白拆分关键词，去掉重复项（不区分大小写）
+func SplitSearchTerms(keyword string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, term := range strings.Fields(keyword) {
+		key := strings.ToLower(term)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// HasCJK 判断字符串是否包含中日韩文字，这类文本没有空格分词
+func HasCJK(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	markdownCodeFence = regexp.MustCompile("(?s)```.*?```")
+	markdownImage     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownHTMLTag   = regexp.MustCompile(`<[^>]+>`)
+	markdownSyntax    = regexp.MustCompile("(?m)^\\s{0,3}(#{1,6}\\s+|>\\s?|[-*+]\\s+|\\d+\\.\\s+)|[*_~`]")
+	whitespace        = regexp.MustCompile(`\s+`)
+)
+
+// MarkdownToText 粗略去掉 Markdown 语法，用于摘要和搜索片段
+func MarkdownToText(markdown string) string {
+	text := markdownCodeFence.ReplaceAllString(markdown, " ")
+	text = markdownImage.ReplaceAllString(text, "$1")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownHTMLTag.ReplaceAllString(text, " ")
+	text = markdownSyntax.ReplaceAllString(text, "")
+	return strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+}
+
+// Highlight 转义文本并用 <mark> 包裹所有命中的检索词
+func Highlight(text string, terms []string) string {
+	return finishMarks(markTerms(text, terms))
+}
+
+// Snippet 截取第一个命中位置附近约 length 个字符并高亮；没有命中时返回空字符串
+func Snippet(text string, terms []string, length int) string {
+	lower := []rune(strings.ToLower(text))
+	runes := []rune(text)
+	// strings.ToLower 可能改变个别字符的长度，此时无法按下标对应，退化为不区分大小写前的原文
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+
+	first := -1
+	for _, term := range terms {
+		if idx := indexRunes(lower, []rune(strings.ToLower(term))); idx >= 0 && (first < 0 || idx < first) {
+			first = idx
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := first - length/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + length
+	if end > len(runes) {
+		end = len(runes)
+		if start = end - length; start < 0 {
+			start = 0
+		}
+	}
+
+	snippet := string(runes[start:end])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return Highlight(snippet, terms)
+}
+
+func markTerms(text string, terms []string) string {
+	if len(terms) == 0 || text == "" {
+		return text
+	}
+
+	lower := []rune(strings.ToLower(text))
+	runes := []rune(text)
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+
+	// 标记每个字符是否属于命中区间，再合并相邻区间
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		needle := []rune(strings.ToLower(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for offset := 0; offset <= len(lower)-len(needle); {
+			idx := indexRunes(lower[offset:], needle)
+			if idx < 0 {
+				break
+			}
+			for i := offset + idx; i < offset+idx+len(needle); i++ {
+				marked[i] = true
+			}
+			offset += idx + len(needle)
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(text) + 16)
+	for i, r := range runes {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(markOpen)
+		}
+		b.WriteRune(r)
+		if marked[i] && (i == len(runes)-1 || !marked[i+1]) {
+			b.WriteString(markClose)
+		}
+	}
+	return b.String()
+}
+
+func finishMarks(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, html.EscapeString(markOpen), "<mark>")
+	return strings.ReplaceAll(s, html.EscapeString(markClose), "</mark>")
+}
+
+func indexRunes(haystack, needle []rune) int {
+	if len(needle) == 0 || len(needle) > len(haystack) {
+		return -1
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/mozillazg/go-unidecode"
+)
+
+// MaxSlugLength 为生成的 slug 最大长度（不含唯一性后缀）
+const MaxSlugLength = 80
+
+// DefaultSlug 在标题转写后没有任何字母数字时使用
+const DefaultSlug = "article"
+
+// Slugify 把标题转写为 ASCII（中文转为拼音）并生成 URL slug，
+// 只包含小写字母、数字和连字符，例如 "你好 Go 语言" -> "ni-hao-go-yu-yan"
+func Slugify(title string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(unidecode.Unidecode(title)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingDash = true
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+		// 尽量在单词边界截断
+		if i := strings.LastIndexByte(slug, '-'); i > MaxSlugLength/2 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	if slug == "" {
+		return DefaultSlug
+	}
+	return slug
+}
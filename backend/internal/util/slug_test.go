@@ -0,0 +1,71 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"ascii", "Hello, World!", "hello-world"},
+		{"collapses separators", "  Go -- 1.22  release  ", "go-1-22-release"},
+		{"chinese to pinyin", "你好 Go 语言", "ni-hao-go-yu-yan"},
+		{"accents", "Crème Brûlée", "creme-brulee"},
+		{"empty", "", DefaultSlug},
+		{"only punctuation", "！？……", DefaultSlug},
+		{"only emoji", "🎉🎉", DefaultSlug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.title); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyTruncation(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{
+			name:  "exactly at limit is kept",
+			title: strings.Repeat("a", MaxSlugLength),
+			want:  strings.Repeat("a", MaxSlugLength),
+		},
+		{
+			name:  "single long word is cut at limit",
+			title: strings.Repeat("a", MaxSlugLength+10),
+			want:  strings.Repeat("a", MaxSlugLength),
+		},
+		{
+			name:  "cut at word boundary",
+			title: strings.Repeat("word ", 30),
+			want:  strings.TrimSuffix(strings.Repeat("word-", 16), "-"),
+		},
+		{
+			name:  "no trailing dash when limit falls on separator",
+			title: strings.Repeat("a", MaxSlugLength-1) + " bcd",
+			want:  strings.Repeat("a", MaxSlugLength-1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.title)
+			if got != tt.want {
+				t.Errorf("Slugify() = %q, want %q", got, tt.want)
+			}
+			if len(got) > MaxSlugLength {
+				t.Errorf("len(Slugify()) = %d, longer than %d", len(got), MaxSlugLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package util
+
+import "strings"
+
+// DescribeUserAgent 将 User-Agent 粗略识别为 "浏览器 on 系统" 形式的设备描述
+func DescribeUserAgent(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/") || strings.Contains(userAgent, "Opera"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "MicroMessenger"):
+		browser = "WeChat"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/") || strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	case strings.HasPrefix(userAgent, "curl/"):
+		browser = "curl"
+	}
+
+	os := "Unknown OS"
+	switch {
+	case strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPad"):
+		os = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		os = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		os = "Windows"
+	case strings.Contains(userAgent, "Mac OS X") || strings.Contains(userAgent, "Macintosh"):
+		os = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		os = "Linux"
+	}
+
+	return browser + " on " + os
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"strings"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	_ "modernc.org/sqlite"
 )
 
@@ -105,6 +105,121 @@ func Migrate(db *sql.DB) error {
 				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
 				UNIQUE(user_id, article_id)
 			)`,
+
+			`CREATE TABLE IF NOT EXISTS sessions (
+				id VARCHAR(36) PRIMARY KEY,
+				user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+				user_agent TEXT DEFAULT '',
+				ip_address VARCHAR(64) DEFAULT '',
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				revoked_at TIMESTAMP WITH TIME ZONE
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS refresh_tokens (
+				id SERIAL PRIMARY KEY,
+				user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				family_id VARCHAR(36) NOT NULL,
+				expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				revoked_at TIMESTAMP WITH TIME ZONE,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS media (
+				id SERIAL PRIMARY KEY,
+				file_name VARCHAR(255) UNIQUE NOT NULL,
+				webp_file_name VARCHAR(255) DEFAULT '',
+				original_name VARCHAR(255) DEFAULT '',
+				mime_type VARCHAR(100) NOT NULL,
+				size BIGINT DEFAULT 0,
+				width INTEGER DEFAULT 0,
+				height INTEGER DEFAULT 0,
+				sha256 VARCHAR(64) UNIQUE NOT NULL,
+				uploader_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+				alt_text TEXT DEFAULT '',
+				files TEXT DEFAULT '[]',
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS tags (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(100) UNIQUE NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS article_tags (
+				article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+				tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+				position INTEGER DEFAULT 0,
+				PRIMARY KEY (article_id, tag_id)
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS article_slugs (
+				slug VARCHAR(255) PRIMARY KEY,
+				article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS article_revisions (
+				id SERIAL PRIMARY KEY,
+				article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+				revision INTEGER NOT NULL,
+				title VARCHAR(200) NOT NULL,
+				slug VARCHAR(255) DEFAULT '',
+				content TEXT NOT NULL,
+				summary VARCHAR(500) DEFAULT '',
+				tags TEXT DEFAULT '[]',
+				status VARCHAR(20) DEFAULT 'draft',
+				cover_image TEXT,
+				changes TEXT DEFAULT '[]',
+				editor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				UNIQUE (article_id, revision)
+			)`,
+
+			// 每个用户对每篇文章只保留一份自动保存，article_id 为 0 表示尚未创建的新文章
+			`CREATE TABLE IF NOT EXISTS article_autosaves (
+				id SERIAL PRIMARY KEY,
+				article_id INTEGER NOT NULL DEFAULT 0,
+				user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				title VARCHAR(200) DEFAULT '',
+				content TEXT DEFAULT '',
+				summary VARCHAR(500) DEFAULT '',
+				tags TEXT DEFAULT '[]',
+				cover_image TEXT,
+				base_version INTEGER NOT NULL DEFAULT 0,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				UNIQUE (user_id, article_id)
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS series (
+				id SERIAL PRIMARY KEY,
+				title VARCHAR(200) UNIQUE NOT NULL,
+				description TEXT DEFAULT '',
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
+
+			// 一篇文章最多属于一个系列
+			`CREATE TABLE IF NOT EXISTS series_articles (
+				series_id INTEGER NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+				article_id INTEGER NOT NULL UNIQUE REFERENCES articles(id) ON DELETE CASCADE,
+				position INTEGER NOT NULL,
+				PRIMARY KEY (series_id, article_id)
+			)`,
+			// 分类可以嵌套，parent_id 为空表示顶级分类
+			`CREATE TABLE IF NOT EXISTS categories (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(100) NOT NULL,
+				slug VARCHAR(100) UNIQUE NOT NULL,
+				description TEXT DEFAULT '',
+				parent_id INTEGER REFERENCES categories(id),
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
 		}
 	} else {
 		// SQLite migrations
@@ -156,6 +271,118 @@ func Migrate(db *sql.DB) error {
 				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 				UNIQUE(user_id, article_id)
 			)`,
+
+			`CREATE TABLE IF NOT EXISTS sessions (
+				id VARCHAR(36) PRIMARY KEY,
+				user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+				user_agent TEXT DEFAULT '',
+				ip_address VARCHAR(64) DEFAULT '',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				last_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				expires_at DATETIME NOT NULL,
+				revoked_at DATETIME
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS refresh_tokens (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				family_id VARCHAR(36) NOT NULL,
+				expires_at DATETIME NOT NULL,
+				revoked_at DATETIME,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS media (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				file_name VARCHAR(255) UNIQUE NOT NULL,
+				webp_file_name VARCHAR(255) DEFAULT '',
+				original_name VARCHAR(255) DEFAULT '',
+				mime_type VARCHAR(100) NOT NULL,
+				size INTEGER DEFAULT 0,
+				width INTEGER DEFAULT 0,
+				height INTEGER DEFAULT 0,
+				sha256 VARCHAR(64) UNIQUE NOT NULL,
+				uploader_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+				alt_text TEXT DEFAULT '',
+				files TEXT DEFAULT '[]',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS tags (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(100) UNIQUE NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS article_tags (
+				article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+				tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+				position INTEGER DEFAULT 0,
+				PRIMARY KEY (article_id, tag_id)
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS article_slugs (
+				slug VARCHAR(255) PRIMARY KEY,
+				article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS article_revisions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+				revision INTEGER NOT NULL,
+				title VARCHAR(200) NOT NULL,
+				slug VARCHAR(255) DEFAULT '',
+				content TEXT NOT NULL,
+				summary VARCHAR(500) DEFAULT '',
+				tags TEXT DEFAULT '[]',
+				status VARCHAR(20) DEFAULT 'draft',
+				cover_image TEXT,
+				changes TEXT DEFAULT '[]',
+				editor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (article_id, revision)
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS article_autosaves (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				article_id INTEGER NOT NULL DEFAULT 0,
+				user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				title VARCHAR(200) DEFAULT '',
+				content TEXT DEFAULT '',
+				summary VARCHAR(500) DEFAULT '',
+				tags TEXT DEFAULT '[]',
+				cover_image TEXT,
+				base_version INTEGER NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (user_id, article_id)
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS series (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				title VARCHAR(200) UNIQUE NOT NULL,
+				description TEXT DEFAULT '',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS series_articles (
+				series_id INTEGER NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+				article_id INTEGER NOT NULL UNIQUE REFERENCES articles(id) ON DELETE CASCADE,
+				position INTEGER NOT NULL,
+				PRIMARY KEY (series_id, article_id)
+			)`,
+			`CREATE TABLE IF NOT EXISTS categories (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(100) NOT NULL,
+				slug VARCHAR(100) UNIQUE NOT NULL,
+				description TEXT DEFAULT '',
+				parent_id INTEGER REFERENCES categories(id),
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
 		}
 	}
 
@@ -204,6 +431,12 @@ func Migrate(db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_likes_article_id ON likes(article_id)`,
 		`CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_title ON articles(title)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_media_created_at ON media(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags(tag_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_article_slugs_article_id ON article_slugs(article_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_article_autosaves_article_id ON article_autosaves(article_id)`,
 	}
 
 	for _, index := range indexes {
@@ -213,6 +446,86 @@ func Migrate(db *sql.DB) error {
 		}
 	}
 
+	if err := migrateSearch(db, dbType); err != nil {
+		return err
+	}
+
+	if err := migrateTags(db, dbType); err != nil {
+		return err
+	}
+
+	if err := migrateSlugs(db, dbType); err != nil {
+		return err
+	}
+
+	if err := addColumn(db, dbType, "articles", "version", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return err
+	}
+
+	if err := addColumn(db, dbType, "articles", "category_id", "INTEGER REFERENCES categories(id)"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_articles_category_id ON articles(category_id)"); err != nil {
+		return fmt.Errorf("failed to create category index: %w", err)
+	}
+
+	if err := addColumn(db, dbType, "articles", "visibility", "VARCHAR(20) NOT NULL DEFAULT 'public'"); err != nil {
+		return err
+	}
+	if err := addColumn(db, dbType, "articles", "password_hash", "VARCHAR(255) NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	timestampType := "DATETIME"
+	if dbType == "postgres" {
+		timestampType = "TIMESTAMP WITH TIME ZONE"
+	}
+	if err := addColumn(db, dbType, "articles", "expires_at", timestampType); err != nil {
+		return err
+	}
+	if err := addColumn(db, dbType, "articles", "expiry_action", "VARCHAR(20) NOT NULL DEFAULT 'unpublish'"); err != nil {
+		return err
+	}
+	if err := migrateRevisionSettings(db, dbType, timestampType); err != nil {
+		return err
+	}
+
+	if err := addColumn(db, dbType, "articles", "pinned", "BOOLEAN NOT NULL DEFAULT FALSE"); err != nil {
+		return err
+	}
+	if err := addColumn(db, dbType, "articles", "pinned_until", timestampType); err != nil {
+		return err
+	}
+	if err := addColumn(db, dbType, "articles", "featured", "BOOLEAN NOT NULL DEFAULT FALSE"); err != nil {
+		return err
+	}
+
+	if err := addColumn(db, dbType, "articles", "content_html", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumn(db, dbType, "articles", "toc", "TEXT NOT NULL DEFAULT '[]'"); err != nil {
+		return err
+	}
+	hasWordCount, err := columnExists(db, dbType, "articles", "word_count")
+	if err != nil {
+		return err
+	}
+	if err := addColumn(db, dbType, "articles", "word_count", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if !hasWordCount {
+		// 字数列首次添加时清空已渲染的 HTML，由 repository.Backfill 重新渲染并统计字数，只执行一次
+		if _, err := db.Exec("UPDATE articles SET content_html = ''"); err != nil {
+			return fmt.Errorf("failed to reset rendered content: %w", err)
+		}
+	}
+	if err := addColumn(db, dbType, "articles", "reading_time", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumn(db, dbType, "articles", "summary_auto", "BOOLEAN NOT NULL DEFAULT FALSE"); err != nil {
+		return err
+	}
+
 	// Add fingerprint column to users table if it doesn't exist
 	rows, err = db.Query("PRAGMA table_info(users)")
 	if err == nil {
@@ -270,3 +583,238 @@ func Migrate(db *sql.DB) error {
 
 	return nil
 }
+
+// migrateSearch 创建文章全文索引：SQLite 使用 FTS5（trigram 分词，支持中文子串匹配）并由触发器同步，
+// Postgres 使用带权重的 tsvector 生成列
+func migrateSearch(db *sql.DB, dbType string) error {
+	if dbType == "postgres" {
+		statements := []string{
+			`ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('simple', coalesce(summary, '')), 'B') ||
+				setweight(to_tsvector('simple', coalesce(content, '')), 'C')
+			) STORED`,
+			`CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector)`,
+		}
+		for _, statement := range statements {
+			if _, err := db.Exec(statement); err != nil {
+				return fmt.Errorf("failed to create search index: %w", err)
+			}
+		}
+		return nil
+	}
+
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'articles_fts'").Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check search index: %w", err)
+	}
+
+	statements := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5(
+			title, summary, content,
+			content='articles', content_rowid='id', tokenize='trigram'
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS articles_fts_insert AFTER INSERT ON articles BEGIN
+			INSERT INTO articles_fts(rowid, title, summary, content) VALUES (new.id, new.title, new.summary, new.content);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS articles_fts_delete AFTER DELETE ON articles BEGIN
+			INSERT INTO articles_fts(articles_fts, rowid, title, summary, content) VALUES ('delete', old.id, old.title, old.summary, old.content);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS articles_fts_update AFTER UPDATE OF title, summary, content ON articles BEGIN
+			INSERT INTO articles_fts(articles_fts, rowid, title, summary, content) VALUES ('delete', old.id, old.title, old.summary, old.content);
+			INSERT INTO articles_fts(rowid, title, summary, content) VALUES (new.id, new.title, new.summary, new.content);
+		END`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+
+	// 首次创建时为已有文章建立索引
+	if exists == 0 {
+		if _, err := db.Exec("INSERT INTO articles_fts(articles_fts) VALUES ('rebuild')"); err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateTags 把旧版 articles.tags 列（Postgres 为 TEXT[]，SQLite 为逗号分隔字符串）中的标签
+// 迁移到 tags/article_tags 表。迁移后清空旧列，因此重复执行不会恢复已被删除或重命名的标签
+func migrateTags(db *sql.DB, dbType string) error {
+	placeholder := func(n int) string {
+		if dbType == "postgres" {
+			return fmt.Sprintf("$%d", n)
+		}
+		return "?"
+	}
+
+	legacy := make(map[int][]string)
+	var query string
+	if dbType == "postgres" {
+		query = "SELECT id, tags FROM articles WHERE tags IS NOT NULL AND cardinality(tags) > 0"
+	} else {
+		query = "SELECT id, tags FROM articles WHERE tags IS NOT NULL AND tags != ''"
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to read legacy tags: %w", err)
+	}
+	for rows.Next() {
+		var id int
+		var tags []string
+		if dbType == "postgres" {
+			err = rows.Scan(&id, pq.Array(&tags))
+		} else {
+			var value string
+			err = rows.Scan(&id, &value)
+			tags = strings.Split(value, ",")
+		}
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read legacy tags: %w", err)
+		}
+		legacy[id] = tags
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read legacy tags: %w", err)
+	}
+	if len(legacy) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for articleID, tags := range legacy {
+		position := 0
+		for _, name := range tags {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, err := tx.Exec("INSERT INTO tags (name) VALUES ("+placeholder(1)+") ON CONFLICT (name) DO NOTHING", name); err != nil {
+				return fmt.Errorf("failed to migrate tags: %w", err)
+			}
+			var tagID int
+			if err := tx.QueryRow("SELECT id FROM tags WHERE name = "+placeholder(1), name).Scan(&tagID); err != nil {
+				return fmt.Errorf("failed to migrate tags: %w", err)
+			}
+			result, err := tx.Exec(
+				"INSERT INTO article_tags (article_id, tag_id, position) VALUES ("+placeholder(1)+", "+placeholder(2)+", "+placeholder(3)+") ON CONFLICT DO NOTHING",
+				articleID, tagID, position,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to migrate tags: %w", err)
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				position++
+			}
+		}
+	}
+
+	if dbType == "postgres" {
+		_, err = tx.Exec("UPDATE articles SET tags = '{}'")
+	} else {
+		_, err = tx.Exec("UPDATE articles SET tags = ''")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to clear legacy tags: %w", err)
+	}
+	return tx.Commit()
+}
+
+// migrateRevisionSettings 为历史版本添加分类、可见性与到期设置的快照列。
+// 已有的版本没有记录这些设置，用文章当前的值填充，恢复时保持不变
+func migrateRevisionSettings(db *sql.DB, dbType, timestampType string) error {
+	columns := []struct{ name, definition string }{
+		{"category_id", "INTEGER"},
+		{"visibility", "VARCHAR(20)"},
+		{"password_hash", "VARCHAR(255)"},
+		{"expires_at", timestampType},
+		{"expiry_action", "VARCHAR(20)"},
+	}
+	for _, column := range columns {
+		if err := addColumn(db, dbType, "article_revisions", column.name, column.definition); err != nil {
+			return err
+		}
+	}
+
+	_, err := db.Exec(`
+		UPDATE article_revisions SET
+			category_id = (SELECT a.category_id FROM articles a WHERE a.id = article_revisions.article_id),
+			visibility = (SELECT a.visibility FROM articles a WHERE a.id = article_revisions.article_id),
+			password_hash = (SELECT a.password_hash FROM articles a WHERE a.id = article_revisions.article_id),
+			expires_at = (SELECT a.expires_at FROM articles a WHERE a.id = article_revisions.article_id),
+			expiry_action = (SELECT a.expiry_action FROM articles a WHERE a.id = article_revisions.article_id)
+		WHERE visibility IS NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate revision settings: %w", err)
+	}
+	return nil
+}
+
+// migrateSlugs 为 articles 添加 slug 列并建立唯一索引
+func migrateSlugs(db *sql.DB, dbType string) error {
+	if dbType == "postgres" {
+		if _, err := db.Exec("ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug VARCHAR(255)"); err != nil {
+			return fmt.Errorf("failed to add slug column: %w", err)
+		}
+	} else {
+		var exists int
+		if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('articles') WHERE name = 'slug'").Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check slug column: %w", err)
+		}
+		if exists == 0 {
+			if _, err := db.Exec("ALTER TABLE articles ADD COLUMN slug VARCHAR(255)"); err != nil {
+				return fmt.Errorf("failed to add slug column: %w", err)
+			}
+		}
+	}
+
+	// 已有文章的 slug 由 repository.Backfill 生成；尚未生成的 slug 为 NULL，不违反唯一索引
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles(slug)"); err != nil {
+		return fmt.Errorf("failed to create slug index: %w", err)
+	}
+	return nil
+}
+
+// addColumn 在列不存在时为表添加列
+func addColumn(db *sql.DB, dbType, table, column, definition string) error {
+	if dbType == "postgres" {
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, column, definition)); err != nil {
+			return fmt.Errorf("failed to add %s column to %s: %w", column, table, err)
+		}
+		return nil
+	}
+
+	exists, err := columnExists(db, dbType, table, column)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+			return fmt.Errorf("failed to add %s column to %s: %w", column, table, err)
+		}
+	}
+	return nil
+}
+
+// columnExists 检查表中是否已有该列，用于只在列首次添加时执行的数据迁移
+func columnExists(db *sql.DB, dbType, table, column string) (bool, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM pragma_table_info('%s') WHERE name = '%s'", table, column)
+	if dbType == "postgres" {
+		query = fmt.Sprintf("SELECT COUNT(*) FROM information_schema.columns WHERE table_name = '%s' AND column_name = '%s'", table, column)
+	}
+	var exists int
+	if err := db.QueryRow(query).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check %s column of %s: %w", column, table, err)
+	}
+	return exists > 0, nil
+}
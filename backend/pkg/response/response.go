@@ -37,6 +37,15 @@ func Error(c *gin.Context, statusCode int, message string) {
 	})
 }
 
+func ErrorWithData(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Message: message,
+		Data:    data,
+		Error:   message,
+	})
+}
+
 func BadRequest(c *gin.Context, message string) {
 	Error(c, http.StatusBadRequest, message)
 }
@@ -1,10 +1,20 @@
 import { apiClient } from './client'
+import { articleAccessHeaders } from '@/utils/articleAccess'
 import type {
   Article,
   ArticleListResponse,
+  ArticleRevision,
+  ArticleAutosave,
+  ArticleAccessToken,
+  PreviewToken,
+  AutosaveRequest,
+  RevisionDiff,
   CreateArticleRequest,
   UpdateArticleRequest,
-  SearchParams
+  SearchParams,
+  Tag,
+  TagCount,
+  UploadedImage
 } from '@/types'
 import { parseDateTimeFromLocal, formatDateTimeForAPI } from '@/utils'
 
@@ -21,8 +31,22 @@ export const articleApi = {
     return apiClient.get(`/articles/${id}`)
   },
 
-  getArticleByTitle: (title: string): Promise<Article> => {
-    return apiClient.get(`/articles/title/${encodeURIComponent(title)}`)
+  // 旧 slug 由后端 301 重定向到当前 slug；accessToken 为受密码保护文章的访问令牌
+  getArticleBySlug: (slug: string, accessToken?: string): Promise<Article> => {
+    return apiClient.get(`/articles/slug/${encodeURIComponent(slug)}`, {
+      headers: accessToken ? { 'X-Article-Token': accessToken } : undefined
+    })
+  },
+
+  getArticleByTitle: (title: string, accessToken?: string): Promise<Article> => {
+    return apiClient.get(`/articles/title/${encodeURIComponent(title)}`, {
+      headers: accessToken ? { 'X-Article-Token': accessToken } : undefined
+    })
+  },
+
+  // 密码正确时返回访问令牌，错误时返回 403
+  unlockArticle: (id: number, password: string): Promise<ArticleAccessToken> => {
+    return apiClient.post(`/articles/${id}/unlock`, { password })
   },
 
   createArticle: (data: CreateArticleRequest): Promise<Article> => {
@@ -62,11 +86,11 @@ export const articleApi = {
   },
 
   likeArticle: (id: number): Promise<void> => {
-    return apiClient.post(`/articles/${id}/like`)
+    return apiClient.post(`/articles/${id}/like`, undefined, { headers: articleAccessHeaders(id) })
   },
 
   unlikeArticle: (id: number): Promise<void> => {
-    return apiClient.delete(`/articles/${id}/like`)
+    return apiClient.delete(`/articles/${id}/like`, { headers: articleAccessHeaders(id) })
   },
 
   searchArticles: (keyword: string, params?: Omit<SearchParams, 'keyword'>): Promise<ArticleListResponse> => {
@@ -89,11 +113,96 @@ export const articleApi = {
     })
   },
 
+  getTrash: (params?: SearchParams): Promise<ArticleListResponse> => {
+    return apiClient.get('/articles/trash', { params })
+  },
+
+  restoreArticle: (id: number): Promise<void> => {
+    return apiClient.post(`/articles/trash/${id}/restore`)
+  },
+
+  // 永久删除，不可恢复
+  purgeArticle: (id: number): Promise<void> => {
+    return apiClient.delete(`/articles/trash/${id}`)
+  },
+
+  getRevisions: (id: number): Promise<ArticleRevision[]> => {
+    return apiClient.get(`/articles/${id}/revisions`)
+  },
+
+  getRevision: (id: number, revision: number): Promise<ArticleRevision> => {
+    return apiClient.get(`/articles/${id}/revisions/${revision}`)
+  },
+
+  // to 省略时与当前内容比较
+  diffRevisions: (id: number, from: number, to?: number): Promise<RevisionDiff> => {
+    return apiClient.get(`/articles/${id}/revisions/diff`, { params: { from, to } })
+  },
+
+  restoreRevision: (id: number, revision: number): Promise<Article> => {
+    return apiClient.post(`/articles/${id}/revisions/${revision}/restore`)
+  },
+
+  // id 省略时为新文章的自动保存
+  getAutosave: (id?: number): Promise<ArticleAutosave> => {
+    return apiClient.get(id ? `/articles/${id}/autosave` : '/articles/autosave')
+  },
+
+  saveAutosave: (data: AutosaveRequest, id?: number): Promise<ArticleAutosave> => {
+    return apiClient.put(id ? `/articles/${id}/autosave` : '/articles/autosave', data)
+  },
+
+  deleteAutosave: (id?: number): Promise<void> => {
+    return apiClient.delete(id ? `/articles/${id}/autosave` : '/articles/autosave')
+  },
+
+  promoteAutosave: (id?: number, status?: Article['status']): Promise<Article> => {
+    return apiClient.post(id ? `/articles/${id}/autosave/promote` : '/articles/autosave/promote', { status })
+  },
+
+  getTags: (): Promise<TagCount[]> => {
+    return apiClient.get('/tags')
+  },
+
+  getAllTags: (): Promise<Tag[]> => {
+    return apiClient.get('/tags/all')
+  },
+
+  renameTag: (id: number, name: string): Promise<Tag> => {
+    return apiClient.put(`/tags/${id}`, { name })
+  },
+
+  mergeTags: (sourceIds: number[], targetId: number): Promise<{ affected_articles: number }> => {
+    return apiClient.post('/tags/merge', { source_ids: sourceIds, target_id: targetId })
+  },
+
+  deleteTag: (id: number): Promise<{ affected_articles: number }> => {
+    return apiClient.delete(`/tags/${id}`)
+  },
+
+  // 生成未发布文章的预览令牌，默认 72 小时后过期
+  createPreview: (id: number, expiresInHours?: number): Promise<PreviewToken> => {
+    return apiClient.post(`/articles/${id}/preview`, { expires_in_hours: expiresInHours })
+  },
+
+  getArticlePreview: (token: string): Promise<Article> => {
+    return apiClient.get(`/articles/preview/${encodeURIComponent(token)}`)
+  },
+
   unpublishArticle: (id: number): Promise<void> => {
     return apiClient.post(`/articles/${id}/unpublish`)
   },
 
-  uploadImage: (file: File): Promise<{ url: string }> => {
+  // pinnedUntil 为空表示一直置顶
+  pinArticle: (id: number, pinned: boolean, pinnedUntil?: string | null): Promise<void> => {
+    return apiClient.put(`/articles/${id}/pin`, { pinned, pinned_until: pinned ? pinnedUntil || null : null })
+  },
+
+  featureArticle: (id: number, featured: boolean): Promise<void> => {
+    return apiClient.put(`/articles/${id}/featured`, { featured })
+  },
+
+  uploadImage: (file: File): Promise<UploadedImage> => {
     const formData = new FormData()
     formData.append('file', file)
     return apiClient.post('/images/upload', formData, {
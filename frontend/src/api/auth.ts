@@ -14,7 +14,7 @@ export const authApi = {
     return apiClient.get('/auth/me')
   },
 
-  refreshToken: (): Promise<{ token: string }> => {
-    return apiClient.post('/auth/refresh')
+  refreshToken: (refreshToken: string): Promise<LoginResponse> => {
+    return apiClient.post('/auth/refresh', { refresh_token: refreshToken })
   }
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+import { apiClient } from './client'
+import type { Category, CategoryRequest } from '@/types'
+
+export const categoriesApi = {
+  // 返回分类树，未登录时文章数只统计已发布的文章
+  getCategoryTree: (): Promise<Category[]> => {
+    return apiClient.get('/categories')
+  },
+
+  createCategory: (data: CategoryRequest): Promise<Category> => {
+    return apiClient.post('/categories', data)
+  },
+
+  updateCategory: (id: number, data: CategoryRequest): Promise<Category> => {
+    return apiClient.put(`/categories/${id}`, data)
+  },
+
+  // 子分类和文章移到上一级分类
+  deleteCategory: (id: number): Promise<void> => {
+    return apiClient.delete(`/categories/${id}`)
+  }
+}
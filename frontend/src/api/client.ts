@@ -6,6 +6,7 @@ const API_BASE_URL = import.meta.env.VITE_API_URL || '/api'
 
 class ApiClient {
   private instance: AxiosInstance
+  private refreshing: Promise<string | null> | null = null
 
   constructor() {
     this.instance = axios.create({
@@ -37,10 +38,20 @@ class ApiClient {
       (response: AxiosResponse) => {
         return response.data.data
       },
-      (error) => {
+      async (error) => {
         console.error('API Error:', error.response);
+        const original = error.config
+        if (error.response?.status === 401 && original && !original._retry && !original.url?.startsWith('/auth/')) {
+          original._retry = true
+          const token = await this.refreshAccessToken()
+          if (token) {
+            original.headers.Authorization = `Bearer ${token}`
+            return this.instance(original)
+          }
+        }
         if (error.response?.status === 401) {
           localStorage.removeItem('token')
+          localStorage.removeItem('refresh_token')
           window.location.href = '/login'
         }
         return Promise.reject(error)
@@ -48,6 +59,29 @@ class ApiClient {
     )
   }
 
+  // 并发的 401 请求共享同一次刷新，避免旧 refresh token 被重复使用而触发整个家族吊销
+  private refreshAccessToken(): Promise<string | null> {
+    const refreshToken = localStorage.getItem('refresh_token')
+    if (!refreshToken) {
+      return Promise.resolve(null)
+    }
+    if (!this.refreshing) {
+      this.refreshing = axios
+        .post(`${API_BASE_URL}/auth/refresh`, { refresh_token: refreshToken })
+        .then((response) => {
+          const data = response.data.data
+          localStorage.setItem('token', data.token)
+          localStorage.setItem('refresh_token', data.refresh_token)
+          return data.token as string
+        })
+        .catch(() => null)
+        .finally(() => {
+          this.refreshing = null
+        })
+    }
+    return this.refreshing
+  }
+
   public get<T = any>(url: string, config?: AxiosRequestConfig): Promise<T> {
     return this.instance.get(url, config)
   }
@@ -1,17 +1,24 @@
 import { apiClient } from './client'
+import { articleAccessHeaders } from '@/utils/articleAccess'
 import type { Comment, CreateCommentRequest, CommentListResponse } from '@/types'
 
 export const commentApi = {
   getCommentsByArticleId: (articleId: number, page: number, pageSize: number): Promise<CommentListResponse> => {
-    return apiClient.get(`/articles/${articleId}/comments`, { params: { page, page_size: pageSize } })
+    return apiClient.get(`/articles/${articleId}/comments`, {
+      params: { page, page_size: pageSize },
+      headers: articleAccessHeaders(articleId)
+    })
   },
 
-  getRepliesByCommentId: (commentId: number, page: number, pageSize: number): Promise<CommentListResponse> => {
-    return apiClient.get(`/comments/${commentId}/replies`, { params: { page, page_size: pageSize } })
+  getRepliesByCommentId: (articleId: number, commentId: number, page: number, pageSize: number): Promise<CommentListResponse> => {
+    return apiClient.get(`/comments/${commentId}/replies`, {
+      params: { page, page_size: pageSize },
+      headers: articleAccessHeaders(articleId)
+    })
   },
 
   createComment: (data: CreateCommentRequest): Promise<Comment> => {
-    return apiClient.post('/comments', data)
+    return apiClient.post('/comments', data, { headers: articleAccessHeaders(data.article_id) })
   },
 
   deleteComment: (id: number, fingerprint?: string): Promise<void> => {
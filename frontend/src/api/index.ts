@@ -1,4 +1,6 @@
 export * from './client'
 export * from './auth'
 export * from './articles'
-export * from './comments'
\ No newline at end of file
+export * from './comments'
+export * from './series'
+export * from './categories'
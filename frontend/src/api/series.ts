@@ -0,0 +1,26 @@
+import { apiClient } from './client'
+import type { Series, SeriesRequest } from '@/types'
+
+export const seriesApi = {
+  // 未登录时只包含已发布的文章
+  getAllSeries: (): Promise<Series[]> => {
+    return apiClient.get('/series')
+  },
+
+  getSeries: (id: number): Promise<Series> => {
+    return apiClient.get(`/series/${id}`)
+  },
+
+  createSeries: (data: SeriesRequest): Promise<Series> => {
+    return apiClient.post('/series', data)
+  },
+
+  // article_ids 为系列中文章的完整顺序
+  updateSeries: (id: number, data: SeriesRequest): Promise<Series> => {
+    return apiClient.put(`/series/${id}`, data)
+  },
+
+  deleteSeries: (id: number): Promise<void> => {
+    return apiClient.delete(`/series/${id}`)
+  }
+}
@@ -12,11 +12,18 @@ const router = createRouter({
       meta: { title: 'home' }
     },
     {
-      path: '/articles/:title',
+      path: '/articles/:slug',
       name: 'article-detail',
       component: () => import('../views/ArticleDetailView.vue'),
       meta: { title: 'article_detail' },
-      props: (route) => ({ title: route.params.title })
+      props: (route) => ({ slug: route.params.slug })
+    },
+    {
+      // 凭预览令牌查看未发布的文章，无需登录
+      path: '/preview/:token',
+      name: 'article-preview',
+      component: () => import('../views/ArticleDetailView.vue'),
+      meta: { title: 'article_preview' }
     },
     {
       path: '/login',
@@ -1,6 +1,7 @@
 import { ref, computed } from 'vue'
 import { defineStore } from 'pinia'
 import { articleApi } from '@/api'
+import { getArticleAccessToken, removeArticleAccessToken, setArticleAccessToken } from '@/utils/articleAccess'
 import type { Article, SearchParams, ArticleListResponse, CreateArticleRequest, UpdateArticleRequest } from '@/types'
 
 export const useArticleStore = defineStore('article', () => {
@@ -79,10 +80,55 @@ export const useArticleStore = defineStore('article', () => {
     }
   }
 
+  // 受密码保护的文章先以锁定状态返回，本地保存过访问令牌时带上令牌重新获取
+  const withAccessToken = async (article: Article, refetch: (token: string) => Promise<Article>) => {
+    const token = article.locked ? getArticleAccessToken(article.id) : null
+    if (!token) return article
+    const unlocked = await refetch(token)
+    if (unlocked.locked) {
+      removeArticleAccessToken(article.id)
+    }
+    return unlocked
+  }
+
+  const fetchArticleBySlug = async (slug: string) => {
+    try {
+      isLoading.value = true
+      const article = await withAccessToken(
+        await articleApi.getArticleBySlug(slug),
+        token => articleApi.getArticleBySlug(slug, token)
+      )
+      currentArticle.value = article
+      return article
+    } catch (error) {
+      console.error('Fetch article by slug error:', error)
+      throw error
+    } finally {
+      isLoading.value = false
+    }
+  }
+
+  const fetchArticlePreview = async (token: string) => {
+    try {
+      isLoading.value = true
+      const article = await articleApi.getArticlePreview(token)
+      currentArticle.value = article
+      return article
+    } catch (error) {
+      console.error('Fetch article preview error:', error)
+      throw error
+    } finally {
+      isLoading.value = false
+    }
+  }
+
   const fetchArticleByTitle = async (title: string) => {
     try {
       isLoading.value = true
-      const article = await articleApi.getArticleByTitle(title)
+      const article = await withAccessToken(
+        await articleApi.getArticleByTitle(title),
+        token => articleApi.getArticleByTitle(title, token)
+      )
       currentArticle.value = article
       return article
     } catch (error) {
@@ -93,6 +139,15 @@ export const useArticleStore = defineStore('article', () => {
     }
   }
 
+  // 输入密码解锁受密码保护的文章，密码错误时抛出 403
+  const unlockArticle = async (article: Article, password: string) => {
+    const access = await articleApi.unlockArticle(article.id, password)
+    setArticleAccessToken(article.id, access.token)
+    const unlocked = await articleApi.getArticleBySlug(article.slug, access.token)
+    currentArticle.value = unlocked
+    return unlocked
+  }
+
   const createArticle = async (articleData: CreateArticleRequest) => {
     try {
       const newArticle = await articleApi.createArticle(articleData)
@@ -104,9 +159,20 @@ export const useArticleStore = defineStore('article', () => {
     }
   }
 
+  // 未指定版本时使用本地缓存中的版本
+  const knownVersion = (id: number) => {
+    if (currentArticle.value?.id === id) {
+      return currentArticle.value.version
+    }
+    return articles.value.find(a => a.id === id)?.version
+  }
+
   const updateArticle = async (articleData: UpdateArticleRequest) => {
     try {
-      const updatedArticle = await articleApi.updateArticle(articleData)
+      const updatedArticle = await articleApi.updateArticle({
+        ...articleData,
+        version: articleData.version ?? knownVersion(articleData.id)
+      })
       const index = articles.value.findIndex(a => a.id === updatedArticle.id)
       if (index !== -1) {
         articles.value[index] = updatedArticle
@@ -123,7 +189,7 @@ export const useArticleStore = defineStore('article', () => {
 
   const publishArticle = async (id: number) => {
     try {
-      const updatedArticle = await articleApi.updateArticle({ id, status: 'published' })
+      const updatedArticle = await articleApi.updateArticle({ id, status: 'published', version: knownVersion(id) })
       const index = articles.value.findIndex(a => a.id === updatedArticle.id)
       if (index !== -1) {
         articles.value[index] = updatedArticle
@@ -190,10 +256,12 @@ export const useArticleStore = defineStore('article', () => {
       if (article) {
         article.status = 'draft'
         article.published_at = undefined
+        article.version++
       }
-      if (currentArticle.value?.id === id) {
+      if (currentArticle.value?.id === id && currentArticle.value !== article) {
         currentArticle.value.status = 'draft'
         currentArticle.value.published_at = undefined
+        currentArticle.value.version++
       }
     } catch (error) {
       console.error('Unpublish article error:', error)
@@ -201,6 +269,33 @@ export const useArticleStore = defineStore('article', () => {
     }
   }
 
+  const pinArticle = async (id: number, pinned: boolean, pinnedUntil?: string | null) => {
+    try {
+      await articleApi.pinArticle(id, pinned, pinnedUntil)
+      const article = articles.value.find(a => a.id === id)
+      if (article) {
+        article.pinned = pinned
+        article.pinned_until = pinned ? pinnedUntil || null : null
+      }
+    } catch (error) {
+      console.error('Pin article error:', error)
+      throw error
+    }
+  }
+
+  const featureArticle = async (id: number, featured: boolean) => {
+    try {
+      await articleApi.featureArticle(id, featured)
+      const article = articles.value.find(a => a.id === id)
+      if (article) {
+        article.featured = featured
+      }
+    } catch (error) {
+      console.error('Feature article error:', error)
+      throw error
+    }
+  }
+
   const searchArticles = async (keyword: string, params?: Omit<SearchParams, 'keyword'>) => {
     try {
       isLoading.value = true
@@ -236,7 +331,10 @@ export const useArticleStore = defineStore('article', () => {
     fetchArticles,
     fetchPublishedArticles,
     fetchArticleById,
+    fetchArticleBySlug,
+    fetchArticlePreview,
     fetchArticleByTitle,
+    unlockArticle,
     createArticle,
     updateArticle,
     publishArticle,
@@ -244,6 +342,8 @@ export const useArticleStore = defineStore('article', () => {
     likeArticle,
     unlikeArticle,
     unpublishArticle,
+    pinArticle,
+    featureArticle,
     searchArticles,
     resetState
   }
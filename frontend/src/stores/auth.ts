@@ -22,6 +22,7 @@ export const useAuthStore = defineStore('auth', () => {
       token.value = response.token
       user.value = response.user
       localStorage.setItem('token', response.token)
+      localStorage.setItem('refresh_token', response.refresh_token)
       
       return response
     } catch (error) {
@@ -40,6 +41,7 @@ export const useAuthStore = defineStore('auth', () => {
       token.value = null
       user.value = null
       localStorage.removeItem('token')
+      localStorage.removeItem('refresh_token')
     }
   }
 
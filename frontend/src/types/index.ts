@@ -16,17 +16,39 @@ export interface LoginRequest {
 
 export interface LoginResponse {
   token: string
+  refresh_token: string
+  refresh_token_expires_at: string
   user: User
 }
 
+export type ArticleVisibility = 'public' | 'unlisted' | 'private' | 'password'
+
+export type ExpiryAction = 'unpublish' | 'archive'
+
+// id 为正文 HTML 中对应标题的锚点
+export interface TOCEntry {
+  level: number
+  id: string
+  text: string
+}
+
 export interface Article {
   id: number
   title: string
+  slug: string
   content: string
   summary: string
   tags: string[]
   author: User
   status: 'draft' | 'published' | 'scheduled'
+  // 乐观锁版本号，更新时原样提交
+  version: number
+  category_id?: number | null
+  category?: ArticleCategory
+  // unlisted 只能通过链接访问；private 仅管理员可见；password 需输入密码查看正文
+  visibility: ArticleVisibility
+  // 受密码保护且尚未解锁，此时 content 为空
+  locked?: boolean
   view_count: number
   like_count: number
   comment_count: number
@@ -34,7 +56,168 @@ export interface Article {
   created_at: string
   updated_at: string
   published_at?: string
+  // 到期后按 expiry_action 自动下线：unpublish 转为草稿，archive 转为不公开列出
+  expires_at?: string | null
+  expiry_action: ExpiryAction
   deleted_at?: string
+  // 置顶的文章在列表中排在最前，pinned_until 为空表示一直置顶
+  pinned: boolean
+  pinned_until?: string | null
+  featured: boolean
+  // 中日韩文字按字计；reading_time 为预计阅读分钟数
+  word_count: number
+  reading_time: number
+  // 摘要由正文自动生成时为 true
+  summary_auto: boolean
+  // 仅文章详情返回：后端渲染并过滤后的 HTML 与由标题生成的目录
+  content_html?: string
+  toc?: TOCEntry[]
+  // 仅搜索结果返回，命中的词用 <mark> 包裹，其余内容已转义
+  highlight?: ArticleHighlight
+  // 仅文章详情返回
+  series?: ArticleSeries
+}
+
+// 文章更新前的快照，changes/editor 描述覆盖这个版本的那次更新
+export interface ArticleRevision {
+  id: number
+  article_id: number
+  revision: number
+  title: string
+  slug: string
+  content?: string
+  summary: string
+  tags: string[]
+  status: Article['status']
+  cover_image?: string | null
+  changes: string[]
+  editor_id?: number | null
+  editor_name?: string
+  created_at: string
+  category_id?: number | null
+  visibility: ArticleVisibility
+  expires_at?: string | null
+  expiry_action: ExpiryAction
+}
+
+export interface DiffLine {
+  type: 'equal' | 'insert' | 'delete'
+  old_line?: number
+  new_line?: number
+  text: string
+}
+
+export interface RevisionDiff {
+  from: number
+  // 0 表示当前内容
+  to: number
+  fields: { field: string; old: unknown; new: unknown }[]
+  lines: DiffLine[]
+  added: number
+  removed: number
+}
+
+// 层级分类，article_count 为直接属于该分类的文章数，total_count 包括所有子分类
+export interface Category {
+  id: number
+  name: string
+  slug: string
+  description: string
+  parent_id: number | null
+  article_count: number
+  total_count: number
+  children: Category[]
+  created_at: string
+}
+
+export interface ArticleCategory {
+  id: number
+  name: string
+  slug: string
+}
+
+export interface CategoryRequest {
+  name: string
+  slug?: string
+  description?: string
+  parent_id?: number | null
+}
+
+export interface SeriesArticle {
+  id: number
+  title: string
+  slug: string
+  status: 'draft' | 'published' | 'scheduled'
+  position: number
+  published_at?: string | null
+}
+
+export interface Series {
+  id: number
+  title: string
+  description: string
+  articles: SeriesArticle[]
+  created_at: string
+  updated_at: string
+}
+
+export interface SeriesRequest {
+  title: string
+  description?: string
+  article_ids: number[]
+}
+
+// 文章在所属系列中的位置及上一篇、下一篇
+export interface ArticleSeries {
+  id: number
+  title: string
+  position: number
+  total: number
+  previous: SeriesArticle | null
+  next: SeriesArticle | null
+}
+
+// 输入密码后获得的访问令牌，通过 X-Article-Token 请求头提交
+export interface ArticleAccessToken {
+  article_id: number
+  token: string
+  expires_at: string
+}
+
+export interface PreviewToken {
+  article_id: number
+  token: string
+  expires_at: string
+}
+
+// 编辑中的工作稿，article_id 为 0 表示尚未创建的新文章；stale 表示文章在自动保存开始后已被修改
+export interface ArticleAutosave {
+  id: number
+  article_id: number
+  user_id: number
+  title: string
+  content: string
+  summary: string
+  tags: string[]
+  cover_image?: string | null
+  base_version: number
+  stale: boolean
+  created_at: string
+  updated_at: string
+}
+
+export interface AutosaveRequest {
+  title: string
+  content: string
+  summary: string
+  tags: string[]
+  cover_image?: string
+  base_version?: number
+}
+
+export interface ArticleHighlight {
+  title: string
+  snippet: string
 }
 
 export interface Comment {
@@ -53,15 +236,27 @@ export interface Comment {
 export interface CreateArticleRequest {
   title: string
   content: string
-  summary: string
+  // 留空时由正文自动生成
+  summary?: string
   tags: string[]
   status: 'draft' | 'published' | 'scheduled'
   cover_image?: string
   published_at?: string
+  // 更新时传 0 清除分类
+  category_id?: number
+  visibility?: ArticleVisibility
+  // 可见性为 password 时必填，更新时省略则保留原密码
+  password?: string
+  // 须晚于当前时间和 published_at
+  expires_at?: string | null
+  expiry_action?: ExpiryAction
 }
 
 export interface UpdateArticleRequest extends Partial<CreateArticleRequest> {
   id: number
+  version?: number
+  // 为 true 时取消到期时间
+  clear_expires_at?: boolean
 }
 
 export interface CreateCommentRequest {
@@ -73,6 +268,8 @@ export interface CreateCommentRequest {
 
 export interface ArticleListResponse {
   articles: Article[]
+  // 仅搜索结果返回
+  facets?: SearchFacets
   total: number
   page: number
   page_size: number
@@ -90,7 +287,80 @@ export interface SearchParams {
   tags?: string[]
   page?: number
   page_size?: number
-  sort_by?: 'created_at' | 'view_count' | 'like_count'
+  sort_by?: 'created_at' | 'view_count' | 'like_count' | 'relevance'
   sort_order?: 'asc' | 'desc'
   include_drafts?: boolean
-}
\ No newline at end of file
+  author?: string
+  year?: number
+  month?: number
+  // 按分类筛选，包括子分类；category 为分类 slug
+  category_id?: number
+  category?: string
+  // 只列出精选文章
+  featured?: boolean
+}
+
+export interface FacetCount {
+  value: string
+  count: number
+}
+
+export interface SearchFacets {
+  tags: FacetCount[]
+  authors: FacetCount[]
+  years: FacetCount[]
+  months: FacetCount[]
+}
+
+export interface TagCount {
+  id: number
+  name: string
+  count: number
+}
+
+export interface Tag {
+  id: number
+  name: string
+  article_count: number
+  published_count: number
+  created_at: string
+}
+export interface ImageVariant {
+  width: number
+  height: number
+  url: string
+  webp_url?: string
+}
+
+export interface UploadedImage {
+  media_id?: number
+  duplicate?: boolean
+  url: string
+  webp_url?: string
+  width: number
+  height: number
+  variants: ImageVariant[]
+  srcset?: string
+  webp_srcset?: string
+}
+
+export interface Media {
+  id: number
+  filename: string
+  webp_filename?: string
+  original_name: string
+  mime_type: string
+  size: number
+  width: number
+  height: number
+  sha256: string
+  uploader_id: number | null
+  uploader_name?: string
+  alt_text: string
+  url: string
+  webp_url?: string
+  variants: ImageVariant[]
+  referenced?: boolean
+  created_at: string
+  updated_at: string
+}
@@ -0,0 +1,20 @@
+// 已解锁文章的访问令牌保存在 sessionStorage，关闭页面后失效
+const accessTokenKey = (id: number) => `article-access-${id}`
+
+export const getArticleAccessToken = (id: number): string | null => {
+  return sessionStorage.getItem(accessTokenKey(id))
+}
+
+export const setArticleAccessToken = (id: number, token: string) => {
+  sessionStorage.setItem(accessTokenKey(id), token)
+}
+
+export const removeArticleAccessToken = (id: number) => {
+  sessionStorage.removeItem(accessTokenKey(id))
+}
+
+// 评论、点赞等接口对受密码保护的文章同样需要访问令牌
+export const articleAccessHeaders = (id: number) => {
+  const token = getArticleAccessToken(id)
+  return token ? { 'X-Article-Token': token } : undefined
+}
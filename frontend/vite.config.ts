@@ -23,6 +23,11 @@ export default defineConfig({
         changeOrigin: true,
         rewrite: (path) => path.replace(/^\/api/, '/api'),
       },
+      // 上传的图片由后端提供
+      '/uploads': {
+        target: 'http://localhost:8888',
+        changeOrigin: true,
+      },
     },
   },
 })